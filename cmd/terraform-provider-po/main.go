@@ -13,7 +13,7 @@ func main() {
 	flag.Parse()
 
 	serveOpts := &plugin.ServeOpts{
-		ProviderFunc: po.Provider,
+		GRPCProviderFunc: po.GRPCProviderServer,
 	}
 	if debugFlag != nil && *debugFlag {
 		plugin.Debug(context.Background(), "registry.terraform.io/feniix/po", serveOpts)
@@ -0,0 +1,1279 @@
+package po
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	po_types_alpha "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+)
+
+func receiverSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of the receiver. Must be unique across all items from the list.",
+			Required:    true,
+		},
+		"webhook_config": {
+			Type:        schema.TypeList,
+			Description: "List of webhook configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: webhookConfigSchema(),
+			},
+		},
+		"slack_config": {
+			Type:        schema.TypeList,
+			Description: "List of Slack configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: slackConfigSchema(),
+			},
+		},
+		"pagerduty_config": {
+			Type:        schema.TypeList,
+			Description: "List of PagerDuty configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: pagerDutyConfigSchema(),
+			},
+		},
+		"email_config": {
+			Type:        schema.TypeList,
+			Description: "List of Email configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: emailConfigSchema(),
+			},
+		},
+		"opsgenie_config": {
+			Type:        schema.TypeList,
+			Description: "List of OpsGenie configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: opsGenieConfigSchema(),
+			},
+		},
+		"wechat_config": {
+			Type:        schema.TypeList,
+			Description: "List of WeChat configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: weChatConfigSchema(),
+			},
+		},
+		"pushover_config": {
+			Type:        schema.TypeList,
+			Description: "List of Pushover configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: pushoverConfigSchema(),
+			},
+		},
+		"victorops_config": {
+			Type:        schema.TypeList,
+			Description: "List of VictorOps configurations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: victorOpsConfigSchema(),
+			},
+		},
+	}
+}
+
+// sendResolvedField mirrors the per-integration default Alertmanager applies
+// when send_resolved is omitted.
+func sendResolvedField(def bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: "Whether or not to notify about resolved alerts.",
+		Optional:    true,
+		Default:     def,
+	}
+}
+
+func secretKeySelectorField(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: description,
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: secretKeySelectorSchema(),
+		},
+	}
+}
+
+func keyValueField(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Description: description,
+		Optional:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+func httpConfigField() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "HTTP client configuration.",
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"basic_auth": {
+					Type:        schema.TypeList,
+					Description: "BasicAuth for the client.",
+					Optional:    true,
+					MaxItems:    1,
+					Elem: &schema.Resource{
+						Schema: basicAuthSchema(),
+					},
+				},
+				"bearer_token_secret": secretKeySelectorField("The secret's key that contains the bearer token to be used by the client for authentication. The secret needs to be in the same namespace as the AlertmanagerConfig object."),
+				"tls_config": {
+					Type:        schema.TypeList,
+					Description: "TLS configuration for the client.",
+					Optional:    true,
+					MaxItems:    1,
+					Elem: &schema.Resource{
+						Schema: safeTLSConfigSchema(),
+					},
+				},
+				"proxy_url": {
+					Type:        schema.TypeString,
+					Description: "Optional proxy URL.",
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+func webhookConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(true),
+		"url": {
+			Type:        schema.TypeString,
+			Description: "The URL to send HTTP POST requests to. url_secret takes precedence over url. One of url_secret and url should be defined.",
+			Optional:    true,
+		},
+		"url_secret":  secretKeySelectorField("The secret's key that contains the webhook URL to send HTTP requests to."),
+		"http_config": httpConfigField(),
+		"max_alerts": {
+			Type:         schema.TypeInt,
+			Description:  "Maximum number of alerts to be sent per webhook message. When 0, all alerts are included.",
+			Optional:     true,
+			ValidateFunc: validateNonNegativeInteger,
+		},
+	}
+}
+
+func slackConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(false),
+		"api_url":       secretKeySelectorField("The secret's key that contains the Slack webhook URL."),
+		"channel": {
+			Type:        schema.TypeString,
+			Description: "The channel or user to send notifications to.",
+			Optional:    true,
+		},
+		"username": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"color": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"title": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"title_link": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"pretext": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"text": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"field": {
+			Type:        schema.TypeList,
+			Description: "A list of Slack fields that are sent with each notification.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"title": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"short": {
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"short_fields": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"footer": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"fallback": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"callback_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"icon_emoji": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"icon_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"image_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"thumb_url": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"link_names": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"mrkdwn_in": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"action": {
+			Type:        schema.TypeList,
+			Description: "A list of Slack actions that are sent with each notification.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"text": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"url": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"style": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"confirm": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"text": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"title": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+								"ok_text": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+								"dismiss_text": {
+									Type:     schema.TypeString,
+									Optional: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"http_config": httpConfigField(),
+	}
+}
+
+func pagerDutyConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(true),
+		"routing_key":   secretKeySelectorField("The secret's key that contains the PagerDuty integration key (when using Events API v2). Either this field or service_key needs to be defined."),
+		"service_key":   secretKeySelectorField("The secret's key that contains the PagerDuty service key (when using integration type \"Prometheus\"). Either this field or routing_key needs to be defined."),
+		"url": {
+			Type:        schema.TypeString,
+			Description: "The URL to send requests to.",
+			Optional:    true,
+		},
+		"client": {
+			Type:        schema.TypeString,
+			Description: "Client identification.",
+			Optional:    true,
+		},
+		"client_url": {
+			Type:        schema.TypeString,
+			Description: "Backlink to the sender of notification.",
+			Optional:    true,
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Description: "Description of the incident.",
+			Optional:    true,
+		},
+		"severity": {
+			Type:        schema.TypeString,
+			Description: "Severity of the incident.",
+			Optional:    true,
+		},
+		"class": {
+			Type:        schema.TypeString,
+			Description: "The class/type of the event.",
+			Optional:    true,
+		},
+		"group": {
+			Type:        schema.TypeString,
+			Description: "A cluster or grouping of sources.",
+			Optional:    true,
+		},
+		"component": {
+			Type:        schema.TypeString,
+			Description: "The part or component of the affected system that is broken.",
+			Optional:    true,
+		},
+		"details":     keyValueField("Arbitrary key/value pairs that provide further detail about the incident."),
+		"http_config": httpConfigField(),
+	}
+}
+
+func emailConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(false),
+		"to": {
+			Type:        schema.TypeString,
+			Description: "The email address to send notifications to.",
+			Optional:    true,
+		},
+		"from": {
+			Type:        schema.TypeString,
+			Description: "The sender address.",
+			Optional:    true,
+		},
+		"hello": {
+			Type:        schema.TypeString,
+			Description: "The hostname to identify to the SMTP server.",
+			Optional:    true,
+		},
+		"smarthost": {
+			Type:        schema.TypeString,
+			Description: "The SMTP host through which emails are sent.",
+			Optional:    true,
+		},
+		"auth_username": {
+			Type:        schema.TypeString,
+			Description: "The username to use for authentication.",
+			Optional:    true,
+		},
+		"auth_password": secretKeySelectorField("The secret's key that contains the password to use for authentication."),
+		"auth_secret":   secretKeySelectorField("The secret's key that contains the CRAM-MD5 secret."),
+		"auth_identity": {
+			Type:        schema.TypeString,
+			Description: "The identity to use for authentication.",
+			Optional:    true,
+		},
+		"headers": keyValueField("Further headers email header key/value pairs. Overrides any headers previously set by the notification implementation."),
+		"html": {
+			Type:        schema.TypeString,
+			Description: "The HTML body of the email notification.",
+			Optional:    true,
+		},
+		"text": {
+			Type:        schema.TypeString,
+			Description: "The text body of the email notification.",
+			Optional:    true,
+		},
+		"require_tls": {
+			Type:        schema.TypeBool,
+			Description: "The SMTP TLS requirement.",
+			Optional:    true,
+			Default:     true,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Description: "TLS configuration.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: safeTLSConfigSchema(),
+			},
+		},
+	}
+}
+
+func opsGenieConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(true),
+		"api_key":       secretKeySelectorField("The secret's key that contains the OpsGenie API key."),
+		"api_url": {
+			Type:        schema.TypeString,
+			Description: "The URL to send OpsGenie API requests to.",
+			Optional:    true,
+		},
+		"message": {
+			Type:        schema.TypeString,
+			Description: "Alert text limited to 130 characters.",
+			Optional:    true,
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Description: "Description of the incident.",
+			Optional:    true,
+		},
+		"source": {
+			Type:        schema.TypeString,
+			Description: "Backlink to the sender of the notification.",
+			Optional:    true,
+		},
+		"tags": {
+			Type:        schema.TypeString,
+			Description: "Comma separated list of tags attached to the notifications.",
+			Optional:    true,
+		},
+		"note": {
+			Type:        schema.TypeString,
+			Description: "Additional alert note.",
+			Optional:    true,
+		},
+		"priority": {
+			Type:        schema.TypeString,
+			Description: "Priority level of alert. Possible values are P1, P2, P3, P4, and P5.",
+			Optional:    true,
+		},
+		"details": keyValueField("A set of arbitrary key/value pairs that provide further detail about the incident."),
+		"responder": {
+			Type:        schema.TypeList,
+			Description: "List of responders responsible for notifications.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:        schema.TypeString,
+						Description: "ID of the responder.",
+						Optional:    true,
+					},
+					"name": {
+						Type:        schema.TypeString,
+						Description: "Name of the responder.",
+						Optional:    true,
+					},
+					"username": {
+						Type:        schema.TypeString,
+						Description: "Username of the responder.",
+						Optional:    true,
+					},
+					"type": {
+						Type:         schema.TypeString,
+						Description:  "Type of responder.",
+						Required:     true,
+						ValidateFunc: validateAttributeValueIsIn([]string{"team", "user", "escalation", "schedule"}),
+					},
+				},
+			},
+		},
+		"http_config": httpConfigField(),
+	}
+}
+
+func weChatConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(false),
+		"api_secret":    secretKeySelectorField("The secret's key that contains the WeChat API key."),
+		"api_url": {
+			Type:        schema.TypeString,
+			Description: "The WeChat API URL.",
+			Optional:    true,
+		},
+		"corp_id": {
+			Type:        schema.TypeString,
+			Description: "The corp id for authentication.",
+			Optional:    true,
+		},
+		"agent_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"to_user": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"to_party": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"to_tag": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"message": {
+			Type:        schema.TypeString,
+			Description: "API request data as defined by the WeChat API.",
+			Optional:    true,
+		},
+		"message_type": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"http_config": httpConfigField(),
+	}
+}
+
+func pushoverConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(true),
+		"user_key":      secretKeySelectorField("The secret's key that contains the recipient user's user key."),
+		"token":         secretKeySelectorField("The secret's key that contains the registered application's API token."),
+		"title": {
+			Type:        schema.TypeString,
+			Description: "Notification title.",
+			Optional:    true,
+		},
+		"message": {
+			Type:        schema.TypeString,
+			Description: "Notification message.",
+			Optional:    true,
+		},
+		"url": {
+			Type:        schema.TypeString,
+			Description: "A supplementary URL shown alongside the message.",
+			Optional:    true,
+		},
+		"url_title": {
+			Type:        schema.TypeString,
+			Description: "A title for supplementary URL, otherwise just the URL is shown.",
+			Optional:    true,
+		},
+		"sound": {
+			Type:        schema.TypeString,
+			Description: "The name of one of the sounds supported by device clients to override the user's default sound choice.",
+			Optional:    true,
+		},
+		"priority": {
+			Type:        schema.TypeString,
+			Description: "Priority.",
+			Optional:    true,
+		},
+		"retry": {
+			Type:        schema.TypeString,
+			Description: "How often the Pushover servers will send the same notification to the user. Must be at least 30 seconds.",
+			Optional:    true,
+		},
+		"expire": {
+			Type:        schema.TypeString,
+			Description: "How long your notification will continue to be retried for, unless the user acknowledges the notification.",
+			Optional:    true,
+		},
+		"html": {
+			Type:        schema.TypeBool,
+			Description: "Whether notification message is HTML or plain text.",
+			Optional:    true,
+		},
+		"http_config": httpConfigField(),
+	}
+}
+
+func victorOpsConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"send_resolved": sendResolvedField(true),
+		"api_key":       secretKeySelectorField("The secret's key that contains the API key to use when talking to the VictorOps API."),
+		"api_url": {
+			Type:        schema.TypeString,
+			Description: "The VictorOps API URL.",
+			Optional:    true,
+		},
+		"routing_key": {
+			Type:        schema.TypeString,
+			Description: "A key used to map the alert to a team.",
+			Required:    true,
+		},
+		"message_type": {
+			Type:        schema.TypeString,
+			Description: "Describes the behavior of the alert (CRITICAL, WARNING, INFO).",
+			Optional:    true,
+		},
+		"entity_display_name": {
+			Type:        schema.TypeString,
+			Description: "Contains summary of the alerted problem.",
+			Optional:    true,
+		},
+		"state_message": {
+			Type:        schema.TypeString,
+			Description: "Contains long explanation of the alerted problem.",
+			Optional:    true,
+		},
+		"monitoring_tool": {
+			Type:        schema.TypeString,
+			Description: "The monitoring tool the state message is from.",
+			Optional:    true,
+		},
+		"custom_fields": keyValueField("Additional custom fields for notification."),
+		"http_config":   httpConfigField(),
+	}
+}
+
+func expandReceivers(receivers []interface{}) ([]po_types_alpha.Receiver, error) {
+	obj := make([]po_types_alpha.Receiver, len(receivers))
+	for i, r := range receivers {
+		in := r.(map[string]interface{})
+		var err error
+		obj[i].Name = in["name"].(string)
+		if obj[i].WebhookConfigs, err = expandWebhookConfigs(in["webhook_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].SlackConfigs, err = expandSlackConfigs(in["slack_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].PagerDutyConfigs, err = expandPagerDutyConfigs(in["pagerduty_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].EmailConfigs, err = expandEmailConfigs(in["email_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].OpsGenieConfigs, err = expandOpsGenieConfigs(in["opsgenie_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].WeChatConfigs, err = expandWeChatConfigs(in["wechat_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].PushoverConfigs, err = expandPushoverConfigs(in["pushover_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if obj[i].VictorOpsConfigs, err = expandVictorOpsConfigs(in["victorops_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenReceivers(in []po_types_alpha.Receiver) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, r := range in {
+		m := make(map[string]interface{})
+		m["name"] = r.Name
+		m["webhook_config"] = flattenWebhookConfigs(r.WebhookConfigs)
+		m["slack_config"] = flattenSlackConfigs(r.SlackConfigs)
+		m["pagerduty_config"] = flattenPagerDutyConfigs(r.PagerDutyConfigs)
+		m["email_config"] = flattenEmailConfigs(r.EmailConfigs)
+		m["opsgenie_config"] = flattenOpsGenieConfigs(r.OpsGenieConfigs)
+		m["wechat_config"] = flattenWeChatConfigs(r.WeChatConfigs)
+		m["pushover_config"] = flattenPushoverConfigs(r.PushoverConfigs)
+		m["victorops_config"] = flattenVictorOpsConfigs(r.VictorOpsConfigs)
+		att[i] = m
+	}
+	return att
+}
+
+func expandSendResolved(in map[string]interface{}) *bool {
+	return ptrToBool(in["send_resolved"].(bool))
+}
+
+func flattenSendResolved(in *bool, def bool) bool {
+	if in == nil {
+		return def
+	}
+	return *in
+}
+
+// expandKeyValues sorts by key so the generated list is stable between applies.
+func expandKeyValues(m map[string]interface{}) []po_types_alpha.KeyValue {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	obj := make([]po_types_alpha.KeyValue, len(keys))
+	for i, k := range keys {
+		obj[i] = po_types_alpha.KeyValue{Key: k, Value: m[k].(string)}
+	}
+	return obj
+}
+
+func flattenKeyValues(in []po_types_alpha.KeyValue) map[string]interface{} {
+	att := make(map[string]interface{}, len(in))
+	for _, kv := range in {
+		att[kv.Key] = kv.Value
+	}
+	return att
+}
+
+func expandHTTPConfig(l []interface{}) (*po_types_alpha.HTTPConfig, error) {
+	if len(l) == 0 || l[0] == nil {
+		return nil, nil
+	}
+	in := l[0].(map[string]interface{})
+	obj := &po_types_alpha.HTTPConfig{}
+	if v, ok := in["basic_auth"].([]interface{}); ok && len(v) > 0 {
+		ba, err := expandBasicAuth(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.BasicAuth = ba
+	}
+	bt, err := expandOptionalSecretKeyRef(in["bearer_token_secret"])
+	if err != nil {
+		return obj, err
+	}
+	obj.BearerTokenSecret = bt
+	if v, ok := in["tls_config"].([]interface{}); ok && len(v) > 0 {
+		tls, err := expandSafeTLSConfig(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.TLSConfig = tls
+	}
+	obj.ProxyURL = in["proxy_url"].(string)
+	return obj, nil
+}
+
+func flattenHTTPConfig(in *po_types_alpha.HTTPConfig) []interface{} {
+	if in == nil {
+		return nil
+	}
+	att := make(map[string]interface{})
+	if in.BasicAuth != nil {
+		att["basic_auth"] = flattenBasicAuth(in.BasicAuth)
+	}
+	att["bearer_token_secret"] = flattenOptionalSecretKeyRef(in.BearerTokenSecret)
+	if in.TLSConfig != nil {
+		att["tls_config"] = flattenSafeTLSConfig(in.TLSConfig)
+	}
+	att["proxy_url"] = in.ProxyURL
+	return []interface{}{att}
+}
+
+func expandWebhookConfigs(l []interface{}) ([]po_types_alpha.WebhookConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.WebhookConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if v, ok := in["url"].(string); ok && v != "" {
+			obj[i].URL = ptrToString(v)
+		}
+		if obj[i].URLSecret, err = expandOptionalSecretKeyRef(in["url_secret"]); err != nil {
+			return obj, err
+		}
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		obj[i].MaxAlerts = int32(in["max_alerts"].(int))
+	}
+	return obj, nil
+}
+
+func flattenWebhookConfigs(in []po_types_alpha.WebhookConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, true)
+		if c.URL != nil {
+			m["url"] = *c.URL
+		}
+		m["url_secret"] = flattenOptionalSecretKeyRef(c.URLSecret)
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		m["max_alerts"] = int(c.MaxAlerts)
+		att[i] = m
+	}
+	return att
+}
+
+func expandSlackConfigs(l []interface{}) ([]po_types_alpha.SlackConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.SlackConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if obj[i].APIURL, err = expandOptionalSecretKeyRef(in["api_url"]); err != nil {
+			return obj, err
+		}
+		obj[i].Channel = in["channel"].(string)
+		obj[i].Username = in["username"].(string)
+		obj[i].Color = in["color"].(string)
+		obj[i].Title = in["title"].(string)
+		obj[i].TitleLink = in["title_link"].(string)
+		obj[i].Pretext = in["pretext"].(string)
+		obj[i].Text = in["text"].(string)
+		for _, f := range in["field"].([]interface{}) {
+			fm := f.(map[string]interface{})
+			obj[i].Fields = append(obj[i].Fields, po_types_alpha.SlackField{
+				Title: fm["title"].(string),
+				Value: fm["value"].(string),
+				Short: ptrToBool(fm["short"].(bool)),
+			})
+		}
+		obj[i].ShortFields = in["short_fields"].(bool)
+		obj[i].Footer = in["footer"].(string)
+		obj[i].Fallback = in["fallback"].(string)
+		obj[i].CallbackID = in["callback_id"].(string)
+		obj[i].IconEmoji = in["icon_emoji"].(string)
+		obj[i].IconURL = in["icon_url"].(string)
+		obj[i].ImageURL = in["image_url"].(string)
+		obj[i].ThumbURL = in["thumb_url"].(string)
+		obj[i].LinkNames = in["link_names"].(bool)
+		if v, ok := in["mrkdwn_in"].([]interface{}); ok && len(v) > 0 {
+			obj[i].MrkdwnIn = expandStringSlice(v)
+		}
+		for _, a := range in["action"].([]interface{}) {
+			am := a.(map[string]interface{})
+			action := po_types_alpha.SlackAction{
+				Type:  am["type"].(string),
+				Text:  am["text"].(string),
+				URL:   am["url"].(string),
+				Style: am["style"].(string),
+				Name:  am["name"].(string),
+				Value: am["value"].(string),
+			}
+			if v, ok := am["confirm"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+				cm := v[0].(map[string]interface{})
+				action.ConfirmField = &po_types_alpha.SlackConfirmationField{
+					Text:        cm["text"].(string),
+					Title:       cm["title"].(string),
+					OkText:      cm["ok_text"].(string),
+					DismissText: cm["dismiss_text"].(string),
+				}
+			}
+			obj[i].Actions = append(obj[i].Actions, action)
+		}
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if err = obj[i].Validate(); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenSlackConfigs(in []po_types_alpha.SlackConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, false)
+		m["api_url"] = flattenOptionalSecretKeyRef(c.APIURL)
+		m["channel"] = c.Channel
+		m["username"] = c.Username
+		m["color"] = c.Color
+		m["title"] = c.Title
+		m["title_link"] = c.TitleLink
+		m["pretext"] = c.Pretext
+		m["text"] = c.Text
+		fields := make([]interface{}, len(c.Fields))
+		for j, f := range c.Fields {
+			fields[j] = map[string]interface{}{
+				"title": f.Title,
+				"value": f.Value,
+				"short": f.Short != nil && *f.Short,
+			}
+		}
+		m["field"] = fields
+		m["short_fields"] = c.ShortFields
+		m["footer"] = c.Footer
+		m["fallback"] = c.Fallback
+		m["callback_id"] = c.CallbackID
+		m["icon_emoji"] = c.IconEmoji
+		m["icon_url"] = c.IconURL
+		m["image_url"] = c.ImageURL
+		m["thumb_url"] = c.ThumbURL
+		m["link_names"] = c.LinkNames
+		m["mrkdwn_in"] = c.MrkdwnIn
+		actions := make([]interface{}, len(c.Actions))
+		for j, a := range c.Actions {
+			am := map[string]interface{}{
+				"type":  a.Type,
+				"text":  a.Text,
+				"url":   a.URL,
+				"style": a.Style,
+				"name":  a.Name,
+				"value": a.Value,
+			}
+			if a.ConfirmField != nil {
+				am["confirm"] = []interface{}{map[string]interface{}{
+					"text":         a.ConfirmField.Text,
+					"title":        a.ConfirmField.Title,
+					"ok_text":      a.ConfirmField.OkText,
+					"dismiss_text": a.ConfirmField.DismissText,
+				}}
+			}
+			actions[j] = am
+		}
+		m["action"] = actions
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		att[i] = m
+	}
+	return att
+}
+
+func expandPagerDutyConfigs(l []interface{}) ([]po_types_alpha.PagerDutyConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.PagerDutyConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if obj[i].RoutingKey, err = expandOptionalSecretKeyRef(in["routing_key"]); err != nil {
+			return obj, err
+		}
+		if obj[i].ServiceKey, err = expandOptionalSecretKeyRef(in["service_key"]); err != nil {
+			return obj, err
+		}
+		obj[i].URL = in["url"].(string)
+		obj[i].Client = in["client"].(string)
+		obj[i].ClientURL = in["client_url"].(string)
+		obj[i].Description = in["description"].(string)
+		obj[i].Severity = in["severity"].(string)
+		obj[i].Class = in["class"].(string)
+		obj[i].Group = in["group"].(string)
+		obj[i].Component = in["component"].(string)
+		obj[i].Details = expandKeyValues(in["details"].(map[string]interface{}))
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenPagerDutyConfigs(in []po_types_alpha.PagerDutyConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, true)
+		m["routing_key"] = flattenOptionalSecretKeyRef(c.RoutingKey)
+		m["service_key"] = flattenOptionalSecretKeyRef(c.ServiceKey)
+		m["url"] = c.URL
+		m["client"] = c.Client
+		m["client_url"] = c.ClientURL
+		m["description"] = c.Description
+		m["severity"] = c.Severity
+		m["class"] = c.Class
+		m["group"] = c.Group
+		m["component"] = c.Component
+		m["details"] = flattenKeyValues(c.Details)
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		att[i] = m
+	}
+	return att
+}
+
+func expandEmailConfigs(l []interface{}) ([]po_types_alpha.EmailConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.EmailConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		obj[i].To = in["to"].(string)
+		obj[i].From = in["from"].(string)
+		obj[i].Hello = in["hello"].(string)
+		obj[i].Smarthost = in["smarthost"].(string)
+		obj[i].AuthUsername = in["auth_username"].(string)
+		if obj[i].AuthPassword, err = expandOptionalSecretKeyRef(in["auth_password"]); err != nil {
+			return obj, err
+		}
+		if obj[i].AuthSecret, err = expandOptionalSecretKeyRef(in["auth_secret"]); err != nil {
+			return obj, err
+		}
+		obj[i].AuthIdentity = in["auth_identity"].(string)
+		obj[i].Headers = expandKeyValues(in["headers"].(map[string]interface{}))
+		obj[i].HTML = in["html"].(string)
+		obj[i].Text = in["text"].(string)
+		obj[i].RequireTLS = ptrToBool(in["require_tls"].(bool))
+		if v, ok := in["tls_config"].([]interface{}); ok && len(v) > 0 {
+			if obj[i].TLSConfig, err = expandSafeTLSConfig(v); err != nil {
+				return obj, err
+			}
+		}
+	}
+	return obj, nil
+}
+
+func flattenEmailConfigs(in []po_types_alpha.EmailConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, false)
+		m["to"] = c.To
+		m["from"] = c.From
+		m["hello"] = c.Hello
+		m["smarthost"] = c.Smarthost
+		m["auth_username"] = c.AuthUsername
+		m["auth_password"] = flattenOptionalSecretKeyRef(c.AuthPassword)
+		m["auth_secret"] = flattenOptionalSecretKeyRef(c.AuthSecret)
+		m["auth_identity"] = c.AuthIdentity
+		m["headers"] = flattenKeyValues(c.Headers)
+		m["html"] = c.HTML
+		m["text"] = c.Text
+		m["require_tls"] = c.RequireTLS == nil || *c.RequireTLS
+		if c.TLSConfig != nil {
+			m["tls_config"] = flattenSafeTLSConfig(c.TLSConfig)
+		}
+		att[i] = m
+	}
+	return att
+}
+
+func expandOpsGenieConfigs(l []interface{}) ([]po_types_alpha.OpsGenieConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.OpsGenieConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if obj[i].APIKey, err = expandOptionalSecretKeyRef(in["api_key"]); err != nil {
+			return obj, err
+		}
+		obj[i].APIURL = in["api_url"].(string)
+		obj[i].Message = in["message"].(string)
+		obj[i].Description = in["description"].(string)
+		obj[i].Source = in["source"].(string)
+		obj[i].Tags = in["tags"].(string)
+		obj[i].Note = in["note"].(string)
+		obj[i].Priority = in["priority"].(string)
+		obj[i].Details = expandKeyValues(in["details"].(map[string]interface{}))
+		for _, r := range in["responder"].([]interface{}) {
+			rm := r.(map[string]interface{})
+			obj[i].Responders = append(obj[i].Responders, po_types_alpha.OpsGenieConfigResponder{
+				ID:       rm["id"].(string),
+				Name:     rm["name"].(string),
+				Username: rm["username"].(string),
+				Type:     rm["type"].(string),
+			})
+		}
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+		if err = obj[i].Validate(); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenOpsGenieConfigs(in []po_types_alpha.OpsGenieConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, true)
+		m["api_key"] = flattenOptionalSecretKeyRef(c.APIKey)
+		m["api_url"] = c.APIURL
+		m["message"] = c.Message
+		m["description"] = c.Description
+		m["source"] = c.Source
+		m["tags"] = c.Tags
+		m["note"] = c.Note
+		m["priority"] = c.Priority
+		m["details"] = flattenKeyValues(c.Details)
+		responders := make([]interface{}, len(c.Responders))
+		for j, r := range c.Responders {
+			responders[j] = map[string]interface{}{
+				"id":       r.ID,
+				"name":     r.Name,
+				"username": r.Username,
+				"type":     r.Type,
+			}
+		}
+		m["responder"] = responders
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		att[i] = m
+	}
+	return att
+}
+
+func expandWeChatConfigs(l []interface{}) ([]po_types_alpha.WeChatConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.WeChatConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if obj[i].APISecret, err = expandOptionalSecretKeyRef(in["api_secret"]); err != nil {
+			return obj, err
+		}
+		obj[i].APIURL = in["api_url"].(string)
+		obj[i].CorpID = in["corp_id"].(string)
+		obj[i].AgentID = in["agent_id"].(string)
+		obj[i].ToUser = in["to_user"].(string)
+		obj[i].ToParty = in["to_party"].(string)
+		obj[i].ToTag = in["to_tag"].(string)
+		obj[i].Message = in["message"].(string)
+		obj[i].MessageType = in["message_type"].(string)
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenWeChatConfigs(in []po_types_alpha.WeChatConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, false)
+		m["api_secret"] = flattenOptionalSecretKeyRef(c.APISecret)
+		m["api_url"] = c.APIURL
+		m["corp_id"] = c.CorpID
+		m["agent_id"] = c.AgentID
+		m["to_user"] = c.ToUser
+		m["to_party"] = c.ToParty
+		m["to_tag"] = c.ToTag
+		m["message"] = c.Message
+		m["message_type"] = c.MessageType
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		att[i] = m
+	}
+	return att
+}
+
+func expandPushoverConfigs(l []interface{}) ([]po_types_alpha.PushoverConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.PushoverConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if obj[i].UserKey, err = expandOptionalSecretKeyRef(in["user_key"]); err != nil {
+			return obj, err
+		}
+		if obj[i].Token, err = expandOptionalSecretKeyRef(in["token"]); err != nil {
+			return obj, err
+		}
+		obj[i].Title = in["title"].(string)
+		obj[i].Message = in["message"].(string)
+		obj[i].URL = in["url"].(string)
+		obj[i].URLTitle = in["url_title"].(string)
+		obj[i].Sound = in["sound"].(string)
+		obj[i].Priority = in["priority"].(string)
+		obj[i].Retry = in["retry"].(string)
+		obj[i].Expire = in["expire"].(string)
+		obj[i].HTML = in["html"].(bool)
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenPushoverConfigs(in []po_types_alpha.PushoverConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, true)
+		m["user_key"] = flattenOptionalSecretKeyRef(c.UserKey)
+		m["token"] = flattenOptionalSecretKeyRef(c.Token)
+		m["title"] = c.Title
+		m["message"] = c.Message
+		m["url"] = c.URL
+		m["url_title"] = c.URLTitle
+		m["sound"] = c.Sound
+		m["priority"] = c.Priority
+		m["retry"] = c.Retry
+		m["expire"] = c.Expire
+		m["html"] = c.HTML
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		att[i] = m
+	}
+	return att
+}
+
+func expandVictorOpsConfigs(l []interface{}) ([]po_types_alpha.VictorOpsConfig, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	obj := make([]po_types_alpha.VictorOpsConfig, len(l))
+	for i, c := range l {
+		in := c.(map[string]interface{})
+		var err error
+		obj[i].SendResolved = expandSendResolved(in)
+		if obj[i].APIKey, err = expandOptionalSecretKeyRef(in["api_key"]); err != nil {
+			return obj, err
+		}
+		obj[i].APIURL = in["api_url"].(string)
+		obj[i].RoutingKey = in["routing_key"].(string)
+		obj[i].MessageType = in["message_type"].(string)
+		obj[i].EntityDisplayName = in["entity_display_name"].(string)
+		obj[i].StateMessage = in["state_message"].(string)
+		obj[i].MonitoringTool = in["monitoring_tool"].(string)
+		obj[i].CustomFields = expandKeyValues(in["custom_fields"].(map[string]interface{}))
+		if obj[i].HTTPConfig, err = expandHTTPConfig(in["http_config"].([]interface{})); err != nil {
+			return obj, err
+		}
+	}
+	return obj, nil
+}
+
+func flattenVictorOpsConfigs(in []po_types_alpha.VictorOpsConfig) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, c := range in {
+		m := make(map[string]interface{})
+		m["send_resolved"] = flattenSendResolved(c.SendResolved, true)
+		m["api_key"] = flattenOptionalSecretKeyRef(c.APIKey)
+		m["api_url"] = c.APIURL
+		m["routing_key"] = c.RoutingKey
+		m["message_type"] = c.MessageType
+		m["entity_display_name"] = c.EntityDisplayName
+		m["state_message"] = c.StateMessage
+		m["monitoring_tool"] = c.MonitoringTool
+		m["custom_fields"] = flattenKeyValues(c.CustomFields)
+		m["http_config"] = flattenHTTPConfig(c.HTTPConfig)
+		att[i] = m
+	}
+	return att
+}
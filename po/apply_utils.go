@@ -0,0 +1,445 @@
+package po
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	applyModeJSONPatch  = "json-patch"
+	applyModeServerSide = "server-side"
+
+	resourceVersionPath = "/metadata/resourceVersion"
+)
+
+// applyPatch builds the body and options of a server-side apply request
+// for obj. Apply requests must carry apiVersion and kind, which typed
+// objects built from the schema leave empty.
+func applyPatch(ac ApplyConfig, obj runtime.Object, gvk apimachineryschema.GroupVersionKind) ([]byte, metav1.PatchOptions, error) {
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, metav1.PatchOptions{}, fmt.Errorf("Failed to marshal %s: %s", gvk.Kind, err)
+	}
+	force := ac.ForceConflicts
+	return data, metav1.PatchOptions{
+		FieldManager: ac.FieldManager,
+		Force:        &force,
+	}, nil
+}
+
+// createDiagnostics turns a failed create into diagnostics, pointing at
+// import when the object already exists.
+func createDiagnostics(summary string, kind string, meta metav1.ObjectMeta, err error) diag.Diagnostics {
+	if errors.IsAlreadyExists(err) {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("%s: %s %s/%s already exists", summary, kind, meta.Namespace, meta.Name),
+				Detail:   fmt.Sprintf("To manage the existing object with Terraform, import it with: terraform import <address> %s", buildId(meta)),
+			},
+		}
+	}
+	return diag.Errorf("%s: %s", summary, err)
+}
+
+// applyDiagnostics turns a failed apply into diagnostics, listing each
+// conflicting field together with the field manager that owns it. guarded
+// tells whether the request carried the resource version from state.
+func applyDiagnostics(summary string, err error, guarded bool) diag.Diagnostics {
+	if guarded && isStaleObjectError(err) {
+		return staleObjectDiagnostics(summary)
+	}
+	status, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsConflict(err) || status.Status().Details == nil {
+		return diag.Errorf("%s: %s", summary, err)
+	}
+	conflicts := make([]string, 0, len(status.Status().Details.Causes))
+	for _, c := range status.Status().Details.Causes {
+		if c.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s: %s", c.Field, c.Message))
+	}
+	if len(conflicts) == 0 {
+		return diag.Errorf("%s: %s", summary, err)
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s: fields are managed by another field manager", summary),
+			Detail: fmt.Sprintf("%s\n\nSet force_conflicts = true in the provider configuration to take ownership of these fields.",
+				strings.Join(conflicts, "\n")),
+		},
+	}
+}
+
+// expectedResourceVersion returns the resource version recorded in state
+// when updates are guarded by it, and an empty string otherwise.
+func expectedResourceVersion(ac ApplyConfig, d *schema.ResourceData) string {
+	if !ac.CheckResourceVersion {
+		return ""
+	}
+	return d.Get("metadata.0.resource_version").(string)
+}
+
+// guardPatch sets metadata.resourceVersion to the version in state. The
+// API server takes it as a precondition of the update the patch results in,
+// and answers with a conflict when the object changed since it was read.
+func guardPatch(ac ApplyConfig, d *schema.ResourceData, ops PatchOperations) PatchOperations {
+	rv := expectedResourceVersion(ac, d)
+	if rv == "" {
+		return ops
+	}
+	return append(PatchOperations{&ReplaceOperation{Path: resourceVersionPath, Value: rv}}, ops...)
+}
+
+// patchDiagnostics turns a failed JSON patch into diagnostics. guarded
+// tells whether the patch carried the resource version from state.
+func patchDiagnostics(summary string, err error, guarded bool) diag.Diagnostics {
+	if guarded && isStaleObjectError(err) {
+		return staleObjectDiagnostics(summary)
+	}
+	return diag.Errorf("%s: %s", summary, err)
+}
+
+// isStaleObjectError reports whether err comes from a resource version
+// precondition: a conflict without field manager causes. Only requests that
+// carried the precondition can fail it, so callers check that first.
+func isStaleObjectError(err error) bool {
+	status, ok := err.(errors.APIStatus)
+	if !ok || !errors.IsConflict(err) {
+		return false
+	}
+	if details := status.Status().Details; details != nil {
+		for _, c := range details.Causes {
+			if c.Type == metav1.CauseTypeFieldManagerConflict {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func staleObjectDiagnostics(summary string) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s: object changed since last refresh", summary),
+			Detail:   "The resource_version in state no longer matches the object in the cluster, so someone else modified it. Refresh the state and review the plan before applying again.",
+		},
+	}
+}
+
+// objectKind describes a kind of monitoring object to the CRUD functions
+// shared by the resources: how it is built from and flattened to the
+// resource data, and how it is reached through the API.
+type objectKind struct {
+	kind string
+	gvr  apimachineryschema.GroupVersionResource
+	gvk  apimachineryschema.GroupVersionKind
+
+	// build returns the object with metadata and a spec returned by
+	// expandSpec.
+	build       func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object
+	expandSpec  func(spec []interface{}) (interface{}, error)
+	flattenSpec func(obj runtime.Object) ([]interface{}, error)
+	client      func(meta interface{}, namespace string) (objectClient, error)
+
+	// rollout is set for kinds the operator runs StatefulSets for, whose
+	// resources have a wait_for_rollout attribute.
+	rollout *objectRollout
+}
+
+// objectClient holds the typed client calls of a kind in a namespace.
+type objectClient struct {
+	get    func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error)
+	list   func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error)
+	create func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error)
+	patch  func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error)
+	delete func(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+// objectRollout tells how to wait for the StatefulSets the operator runs
+// for an object.
+type objectRollout struct {
+	// statefulSets selects the StatefulSets of the named object.
+	statefulSets func(name string) metav1.ListOptions
+	// expected returns the number of StatefulSets and the replicas of each
+	// that obj asks for.
+	expected func(obj runtime.Object) (int, int32)
+	// paused, when set, reports whether the planned object is paused. The
+	// operator does not reconcile paused objects, so there is nothing to
+	// wait for.
+	paused func(d *schema.ResourceData) bool
+}
+
+// resource completes r with the CRUD functions, plan checks and importer
+// of the kind.
+func (k *objectKind) resource(r *schema.Resource) *schema.Resource {
+	r.CreateContext = traceOperation(k.kind, "create", k.create)
+	r.ReadContext = traceOperation(k.kind, "read", k.read)
+	r.UpdateContext = traceOperation(k.kind, "update", k.update)
+	r.DeleteContext = traceOperation(k.kind, "delete", k.delete)
+	r.CustomizeDiff = tracePlan(k.kind, customdiff.Sequence(planDefaults, planChecks(k.gvr, k.expandSpec)))
+	r.Schema["applied_default_labels"] = appliedDefaultsSchema("labels")
+	r.Schema["applied_default_annotations"] = appliedDefaultsSchema("annotations")
+	r.Importer = &schema.ResourceImporter{
+		StateContext: schema.ImportStatePassthroughContext,
+	}
+	return r
+}
+
+// expand builds the object from the resource data.
+func (k *objectKind) expand(d *schema.ResourceData, meta interface{}) (runtime.Object, error) {
+	spec, err := k.expandSpec(d.Get("spec").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	return k.build(expandMetadata(d.Get("metadata").([]interface{}), meta.(KubeClientsets).MetadataConfig()), spec), nil
+}
+
+func (k *objectKind) create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	obj, err := k.expand(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	metadata := objectMeta(obj)
+	c, err := k.client(meta, metadata.Namespace)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	summary := "Failed to create " + k.kind
+	log.Printf("[INFO] Creating new %s: %s", k.kind, objectSummary(obj))
+	// Objects are created with a plain create in both modes, so that one
+	// that already exists fails rather than being adopted.
+	ac := meta.(KubeClientsets).ApplyConfig()
+	opts := metav1.CreateOptions{}
+	if ac.ServerSide {
+		opts.FieldManager = ac.FieldManager
+	}
+	out, err := c.create(ctx, obj, opts)
+	if err != nil {
+		return createDiagnostics(summary, k.kind, metadata, err)
+	}
+	log.Printf("[INFO] Submitted new %s: %s", k.kind, objectSummary(out))
+	d.SetId(buildId(objectMeta(out)))
+	forgetObject(meta, k.gvr, metadata.Namespace, metadata.Name)
+
+	if ac.ServerSide {
+		// Applying the same object makes the field manager own its fields
+		// as applied, so later applies can remove them.
+		data, opts, err := applyPatch(ac, obj, k.gvk)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		out, err = c.patch(ctx, metadata.Name, pkgApi.ApplyPatchType, data, opts)
+		if err != nil {
+			return applyDiagnostics(summary, err, false)
+		}
+	}
+
+	if k.waitsForRollout(d) {
+		if err := k.waitForRollout(ctx, meta, out, nil, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return k.read(ctx, d, meta)
+}
+
+func (k *objectKind) read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := unconfiguredRead(d, meta); diags != nil {
+		return diags
+	}
+	namespace, name, err := idParts(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	c, err := k.client(meta, namespace)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Reading %s %s", k.kind, name)
+	obj, err := getObject(ctx, meta, k.gvr, namespace, name, func() (runtime.Object, error) {
+		return c.get(ctx, name, metav1.GetOptions{})
+	}, func(opts metav1.ListOptions) (runtime.Object, error) {
+		return c.list(ctx, opts)
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			d.SetId("")
+			return diag.Diagnostics{}
+		}
+		log.Printf("[DEBUG] Received error: %#v", err)
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Received %s: %s", k.kind, objectSummary(obj))
+	mc := meta.(KubeClientsets).MetadataConfig()
+	metadata := objectMeta(obj)
+	err = d.Set("applied_default_labels", appliedDefaults(metadata.Labels, mc.DefaultLabels, d.Get("metadata.0.labels").(map[string]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("applied_default_annotations", appliedDefaults(metadata.Annotations, mc.DefaultAnnotations, d.Get("metadata.0.annotations").(map[string]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("metadata", flattenMetadata(metadata, d, mc))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	spec, err := k.flattenSpec(obj)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("spec", spec)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func (k *objectKind) update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	namespace, name, err := idParts(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	c, err := k.client(meta, namespace)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Without recording the StatefulSets first, the wait below would see
+	// the rollout from before the update as complete and return at once.
+	var before map[string]statefulSetRevision
+	waitForRollout := d.HasChange("spec") && k.waitsForRollout(d)
+	if waitForRollout {
+		kc, err := meta.(KubeClientsets).MainClientset()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		before, err = snapshotStatefulSets(ctx, kc, namespace, k.rollout.statefulSets(name))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	summary := "Failed to update " + k.kind
+	var out runtime.Object
+	if ac := meta.(KubeClientsets).ApplyConfig(); ac.ServerSide {
+		obj, err := k.expand(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		rv := expectedResourceVersion(ac, d)
+		obj.(metav1.Object).SetResourceVersion(rv)
+		data, opts, err := applyPatch(ac, obj, k.gvk)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		err = retryOnConflict(ctx, meta, func() error {
+			out, err = c.patch(ctx, name, pkgApi.ApplyPatchType, data, opts)
+			return err
+		})
+		if err != nil {
+			return applyDiagnostics(summary, err, rv != "")
+		}
+	} else {
+		err = retryOnConflict(ctx, meta, func() error {
+			live, err := c.get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			ops := guardPatch(ac, d, patchMetadata("metadata.0.", "/metadata/", d, objectMeta(live), meta.(KubeClientsets).MetadataConfig()))
+			if d.HasChange("spec") {
+				spec, err := k.expandSpec(d.Get("spec").([]interface{}))
+				if err != nil {
+					return err
+				}
+				ops = append(ops, replace(spec))
+			}
+			data, err := ops.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			out, err = c.patch(ctx, name, pkgApi.JSONPatchType, data, metav1.PatchOptions{})
+			return err
+		})
+		if err != nil {
+			return patchDiagnostics(summary, err, expectedResourceVersion(ac, d) != "")
+		}
+	}
+	log.Printf("[INFO] Submitted updated %s: %s", k.kind, objectSummary(out))
+	d.SetId(buildId(objectMeta(out)))
+	forgetObject(meta, k.gvr, namespace, name)
+
+	if waitForRollout {
+		if err := k.waitForRollout(ctx, meta, out, before, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return k.read(ctx, d, meta)
+}
+
+func (k *objectKind) delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	namespace, name, err := idParts(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	c, err := k.client(meta, namespace)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Deleting %s: %#v", k.kind, name)
+	err = c.delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] %s %s deleted", k.kind, name)
+
+	d.SetId("")
+	return nil
+}
+
+func (k *objectKind) waitsForRollout(d *schema.ResourceData) bool {
+	if k.rollout == nil || !d.Get("wait_for_rollout").(bool) {
+		return false
+	}
+	if k.rollout.paused != nil && k.rollout.paused(d) {
+		log.Printf("[DEBUG] Not waiting for paused %s %s to roll out", k.kind, d.Id())
+		return false
+	}
+	return true
+}
+
+// waitForRollout waits for the StatefulSets of out to run the replicas
+// requested in its spec. before holds the StatefulSets as they were before
+// an update, nil on create.
+func (k *objectKind) waitForRollout(ctx context.Context, meta interface{}, out runtime.Object, before map[string]statefulSetRevision, timeout time.Duration) error {
+	conn, err := meta.(KubeClientsets).MainClientset()
+	if err != nil {
+		return err
+	}
+	metadata := objectMeta(out)
+	log.Printf("[DEBUG] Waiting for %s %s to roll out", k.kind, metadata.Name)
+	count, replicas := k.rollout.expected(out)
+	return waitForStatefulSetsRollout(ctx, conn, metadata.Namespace, k.rollout.statefulSets(metadata.Name), count, replicas, before, timeout)
+}
+
+// objectMeta returns the metadata of a typed object.
+func objectMeta(obj runtime.Object) metav1.ObjectMeta {
+	return *obj.(metav1.ObjectMetaAccessor).GetObjectMeta().(*metav1.ObjectMeta)
+}
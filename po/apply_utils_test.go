@@ -0,0 +1,97 @@
+package po
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	monitoring "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+	restclient "k8s.io/client-go/rest"
+)
+
+// staleObjectServer answers every request like the API server answers an
+// update whose resourceVersion precondition no longer holds.
+func staleObjectServer(body *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		*body = string(b)
+		status := errors.NewConflict(po_types.SchemeGroupVersion.WithResource(po_types.ServiceMonitorName).GroupResource(), "example",
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again")).Status()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(&status)
+	}))
+}
+
+func TestGuardedPatchReportsStaleObject(t *testing.T) {
+	var sent string
+	srv := staleObjectServer(&sent)
+	defer srv.Close()
+	conn, err := monitoring.NewForConfig(&restclient.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, resourcePoServiceMonitor().Schema, map[string]interface{}{
+		"metadata": []interface{}{map[string]interface{}{"name": "example"}},
+	})
+	d.Set("metadata", []interface{}{map[string]interface{}{"name": "example", "resource_version": "42"}})
+	ac := ApplyConfig{CheckResourceVersion: true}
+
+	data, err := guardPatch(ac, d, PatchOperations{}).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.MonitoringV1().ServiceMonitors("default").Patch(context.Background(), "example", pkgApi.JSONPatchType, data, metav1.PatchOptions{})
+	if err == nil {
+		t.Fatal("expected the patch to fail")
+	}
+	if want := `[{"path":"/metadata/resourceVersion","value":"42","op":"replace"}]`; sent != want {
+		t.Fatalf("expected the patch %s, got %s", want, sent)
+	}
+
+	diags := patchDiagnostics("Failed to update ServiceMonitor", err, true)
+	if len(diags) != 1 || !strings.HasSuffix(diags[0].Summary, "object changed since last refresh") {
+		t.Fatalf("expected a stale object diagnostic, got %#v", diags)
+	}
+	diags = patchDiagnostics("Failed to update ServiceMonitor", err, false)
+	if len(diags) != 1 || strings.HasSuffix(diags[0].Summary, "object changed since last refresh") {
+		t.Fatalf("expected the conflict to be reported as is without the guard, got %#v", diags)
+	}
+}
+
+func TestUnguardedPatchHasNoPrecondition(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePoServiceMonitor().Schema, map[string]interface{}{
+		"metadata": []interface{}{map[string]interface{}{"name": "example"}},
+	})
+	d.Set("metadata", []interface{}{map[string]interface{}{"name": "example", "resource_version": "42"}})
+
+	data, err := guardPatch(ApplyConfig{}, d, PatchOperations{}).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[]" {
+		t.Fatalf("expected no operations, got %s", data)
+	}
+}
+
+func TestPausedAlertmanagerSkipsRolloutWait(t *testing.T) {
+	for _, paused := range []bool{false, true} {
+		d := schema.TestResourceDataRaw(t, resourcePoAlertmanager().Schema, map[string]interface{}{
+			"metadata": []interface{}{map[string]interface{}{"name": "example"}},
+			"spec":     []interface{}{map[string]interface{}{"paused": paused}},
+		})
+		if got := alertmanagerKind.waitsForRollout(d); got == paused {
+			t.Fatalf("expected waiting for the rollout to be %t with paused = %t", !paused, paused)
+		}
+	}
+}
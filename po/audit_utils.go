@@ -0,0 +1,216 @@
+package po
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	po_types_alpha "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+)
+
+// monitoringKinds maps the resources the provider manages to their kinds.
+var monitoringKinds = map[string]string{
+	po_types.ServiceMonitorName:           po_types.ServiceMonitorsKind,
+	po_types.PodMonitorName:               po_types.PodMonitorsKind,
+	po_types.PrometheusRuleName:           po_types.PrometheusRuleKind,
+	po_types.ProbeName:                    po_types.ProbesKind,
+	po_types.PrometheusName:               po_types.PrometheusesKind,
+	po_types.AlertmanagerName:             po_types.AlertmanagersKind,
+	po_types.ThanosRulerName:              po_types.ThanosRulerKind,
+	po_types_alpha.AlertmanagerConfigName: po_types_alpha.AlertmanagerConfigKind,
+}
+
+// auditEntry is one line of the audit log. User is the kubeconfig user
+// (auth-info) name, or "in-cluster", as the provider has no way to learn the
+// identity the API server authenticates the credentials as.
+type auditEntry struct {
+	Timestamp   string          `json:"timestamp"`
+	User        string          `json:"user"`
+	Impersonate string          `json:"impersonate,omitempty"`
+	Server      string          `json:"server"`
+	Kind        string          `json:"kind"`
+	Namespace   string          `json:"namespace"`
+	Name        string          `json:"name"`
+	Operation   string          `json:"operation"`
+	PatchType   string          `json:"patch_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Status      int             `json:"status,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// auditLog appends an entry for every create, patch and delete of a
+// monitoring object to a file, one JSON document per line.
+type auditLog struct {
+	mu          sync.Mutex
+	path        string
+	user        string
+	impersonate string
+	server      string
+}
+
+// auditTransport records the mutating requests sent for monitoring objects,
+// whether they succeed or fail. A request that cannot be audited fails: it
+// is not sent when the log cannot be opened, and returns an error when the
+// entry cannot be written once it was sent.
+type auditTransport struct {
+	rt    http.RoundTripper
+	audit *auditLog
+}
+
+// auditError is returned for requests that could not be audited. They are
+// not retried.
+type auditError struct {
+	err error
+}
+
+func (e *auditError) Error() string {
+	return e.err.Error()
+}
+
+func newAuditTransport(audit *auditLog) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &auditTransport{rt: rt, audit: audit}
+	}
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry, ok := auditEntryFor(req)
+	if !ok {
+		return t.rt.RoundTrip(req)
+	}
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if json.Valid(body) {
+			entry.Body = body
+			if entry.Name == "" {
+				var obj struct {
+					Metadata struct {
+						Name string `json:"name"`
+					} `json:"metadata"`
+				}
+				if json.Unmarshal(body, &obj) == nil {
+					entry.Name = obj.Metadata.Name
+				}
+			}
+		}
+	}
+
+	f, err := t.audit.open()
+	if err != nil {
+		return nil, &auditError{fmt.Errorf("Failed to open audit log, request not sent: %s", err)}
+	}
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Status = resp.StatusCode
+	}
+	if werr := t.audit.write(f, entry); werr != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, &auditError{fmt.Errorf("Failed to write audit log of %s %s/%s (status %d): %s", entry.Operation, entry.Namespace, entry.Name, entry.Status, werr)}
+	}
+	return resp, err
+}
+
+// auditEntryFor returns the entry of a request that changes a monitoring
+// object.
+func auditEntryFor(req *http.Request) (auditEntry, bool) {
+	var op string
+	switch req.Method {
+	case http.MethodPost:
+		op = "create"
+	case http.MethodPatch:
+		op = "patch"
+	case http.MethodPut:
+		op = "update"
+	case http.MethodDelete:
+		op = "delete"
+	default:
+		return auditEntry{}, false
+	}
+	kind, namespace, name, ok := parseObjectPath(req.URL.Path)
+	if !ok {
+		return auditEntry{}, false
+	}
+	entry := auditEntry{
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+		Operation: op,
+	}
+	if op == "patch" {
+		entry.PatchType = req.Header.Get("Content-Type")
+	}
+	return entry, true
+}
+
+// checkAuditLog fails when the audit log at path cannot be opened for
+// appending, so a bad path is reported when the provider is configured
+// rather than on the first change.
+func checkAuditLog(path string) error {
+	f, err := (&auditLog{path: path}).open()
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (a *auditLog) open() (*os.File, error) {
+	return os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+}
+
+// write appends entry to f, which it closes.
+func (a *auditLog) write(f *os.File, entry auditEntry) error {
+	entry.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	entry.User = a.user
+	entry.Impersonate = a.impersonate
+	entry.Server = a.server
+	line, err := json.Marshal(entry)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseObjectPath returns the kind, namespace and name of the monitoring
+// object addressed by an API path of the form
+// /apis/monitoring.coreos.com/<version>/namespaces/<namespace>/<resource>[/<name>].
+// The name is empty for collection requests.
+func parseObjectPath(path string) (string, string, string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 6 || parts[0] != "apis" || parts[1] != po_types.SchemeGroupVersion.Group || parts[3] != "namespaces" {
+		return "", "", "", false
+	}
+	kind, ok := monitoringKinds[parts[5]]
+	if !ok {
+		return "", "", "", false
+	}
+	var name string
+	if len(parts) > 6 {
+		name = parts[6]
+	}
+	return kind, parts[4], name, true
+}
@@ -0,0 +1,134 @@
+package po
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// listCachePageSize is the page size used to fill the LIST cache.
+const listCachePageSize = 500
+
+// listCache serves reads from a single LIST per resource and namespace, so
+// refreshing N objects of one namespace costs one request instead of N.
+// Objects written by the provider are always read back with a GET, since
+// the cached LIST predates the write.
+type listCache struct {
+	mu      sync.Mutex
+	lists   map[string]*listCacheEntry
+	written map[string]bool
+}
+
+type listCacheEntry struct {
+	done    chan struct{}
+	objects map[string]runtime.Object
+	err     error
+}
+
+func newListCache() *listCache {
+	return &listCache{
+		lists:   make(map[string]*listCacheEntry),
+		written: make(map[string]bool),
+	}
+}
+
+func listCacheKey(gvr apimachineryschema.GroupVersionResource, namespace string) string {
+	return gvr.String() + "/" + namespace
+}
+
+// getObject returns the named object, from the LIST cache when it is
+// enabled and with get otherwise. Objects missing from the cached LIST are
+// reported with a NotFound error, like get would.
+func getObject(ctx context.Context, meta interface{}, gvr apimachineryschema.GroupVersionResource, namespace, name string,
+	get func() (runtime.Object, error), list func(opts metav1.ListOptions) (runtime.Object, error)) (runtime.Object, error) {
+	c := meta.(KubeClientsets).ListCache()
+	if c == nil {
+		return get()
+	}
+	key := listCacheKey(gvr, namespace)
+
+	c.mu.Lock()
+	if c.written[key+"/"+name] {
+		c.mu.Unlock()
+		return get()
+	}
+	entry, ok := c.lists[key]
+	if !ok {
+		entry = &listCacheEntry{done: make(chan struct{})}
+		c.lists[key] = entry
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		entry.objects, entry.err = listObjects(ctx, list)
+		if entry.err != nil {
+			c.mu.Lock()
+			delete(c.lists, key)
+			c.mu.Unlock()
+		}
+		close(entry.done)
+	}
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	obj, ok := entry.objects[name]
+	if !ok {
+		return nil, errors.NewNotFound(gvr.GroupResource(), name)
+	}
+	return obj, nil
+}
+
+func listObjects(ctx context.Context, list func(opts metav1.ListOptions) (runtime.Object, error)) (map[string]runtime.Object, error) {
+	objects := make(map[string]runtime.Object)
+	opts := metav1.ListOptions{Limit: listCachePageSize}
+	for {
+		out, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		items, err := apimeta.ExtractList(out)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			accessor, err := apimeta.Accessor(item)
+			if err != nil {
+				return nil, err
+			}
+			objects[accessor.GetName()] = item
+		}
+		listMeta, err := apimeta.ListAccessor(out)
+		if err != nil {
+			return nil, err
+		}
+		if listMeta.GetContinue() == "" {
+			break
+		}
+		opts.Continue = listMeta.GetContinue()
+	}
+	log.Printf("[DEBUG] Cached %d objects", len(objects))
+	return objects, nil
+}
+
+// forgetObject makes later reads of an object written by the provider
+// bypass the LIST cache.
+func forgetObject(meta interface{}, gvr apimachineryschema.GroupVersionResource, namespace, name string) {
+	c := meta.(KubeClientsets).ListCache()
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.written[listCacheKey(gvr, namespace)+"/"+name] = true
+}
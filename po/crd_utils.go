@@ -0,0 +1,166 @@
+package po
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdSchemaCache holds the spec schema of each CRD for the lifetime of the
+// provider, so it is read once per plan rather than once per resource.
+type crdSchemaCache struct {
+	mu      sync.Mutex
+	schemas map[apimachineryschema.GroupVersionResource]*apiextensionsv1.JSONSchemaProps
+}
+
+func newCRDSchemaCache() *crdSchemaCache {
+	return &crdSchemaCache{
+		schemas: make(map[apimachineryschema.GroupVersionResource]*apiextensionsv1.JSONSchemaProps),
+	}
+}
+
+// validateSpecAgainstCRD returns a CustomizeDiffFunc that expands the
+// planned spec and checks it against the openAPIV3Schema of the CRD serving
+// gvr, so fields the installed operator version would prune are reported
+// during plan rather than silently dropped on apply. Only new objects and
+// changed specs are checked.
+func validateSpecAgainstCRD(gvr apimachineryschema.GroupVersionResource, expand func([]interface{}) (interface{}, error)) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !meta.(KubeClientsets).ValidateCRDSchema() || !d.NewValueKnown("spec") {
+			return nil
+		}
+		if d.Id() != "" && !d.HasChange("spec") {
+			return nil
+		}
+		spec, err := expand(d.Get("spec").([]interface{}))
+		if err != nil {
+			return err
+		}
+		specSchema, err := cachedCRDSpecSchema(ctx, meta, gvr)
+		if errors.IsForbidden(err) {
+			log.Printf("[WARN] Skipping validation against the %s.%s CRD: %s", gvr.Resource, gvr.Group, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if specSchema == nil {
+			log.Printf("[DEBUG] CRD %s.%s has no schema for spec, skipping validation", gvr.Resource, gvr.Group)
+			return nil
+		}
+		raw, err := json.Marshal(spec)
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		unknown := unknownSchemaFields(value, specSchema, "spec")
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("The %s.%s CRD installed in the cluster does not know the following fields, they would be pruned on apply: %s. Upgrade the prometheus-operator CRDs or remove these fields.",
+				gvr.Resource, gvr.Group, strings.Join(unknown, ", "))
+		}
+		return nil
+	}
+}
+
+// cachedCRDSpecSchema returns the spec schema of gvr from the provider
+// cache, reading the CRD on first use. Errors are not cached.
+func cachedCRDSpecSchema(ctx context.Context, meta interface{}, gvr apimachineryschema.GroupVersionResource) (*apiextensionsv1.JSONSchemaProps, error) {
+	c := meta.(KubeClientsets).CRDSchemaCache()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.schemas[gvr]; ok {
+		return s, nil
+	}
+	s, err := crdSpecSchema(ctx, meta, gvr)
+	if err != nil {
+		return nil, err
+	}
+	c.schemas[gvr] = s
+	return s, nil
+}
+
+// crdSpecSchema returns the schema of the spec field for the version of gvr
+// served by the installed CRD.
+func crdSpecSchema(ctx context.Context, meta interface{}, gvr apimachineryschema.GroupVersionResource) (*apiextensionsv1.JSONSchemaProps, error) {
+	conn, err := meta.(KubeClientsets).ExtensionsClientset()
+	if err != nil {
+		return nil, err
+	}
+	name := gvr.Resource + "." + gvr.Group
+	crd, err := conn.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("The %s CRD is not installed in the cluster. Install the prometheus-operator CRDs before managing %s.", name, gvr.Resource)
+		}
+		if errors.IsForbidden(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Failed to read CRD %s: %s", name, err)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name != gvr.Version {
+			continue
+		}
+		if !v.Served {
+			return nil, fmt.Errorf("The %s CRD installed in the cluster does not serve version %s.", name, gvr.Version)
+		}
+		if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			return nil, nil
+		}
+		if s, ok := v.Schema.OpenAPIV3Schema.Properties["spec"]; ok {
+			return &s, nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("The %s CRD installed in the cluster does not define version %s.", name, gvr.Version)
+}
+
+// unknownSchemaFields walks value along s and returns the paths of the
+// fields the structural schema does not declare.
+func unknownSchemaFields(value interface{}, s *apiextensionsv1.JSONSchemaProps, path string) []string {
+	if s == nil || (s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields) || s.XEmbeddedResource {
+		return nil
+	}
+	unknown := make([]string, 0)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if prop, ok := s.Properties[k]; ok {
+				unknown = append(unknown, unknownSchemaFields(item, &prop, path+"."+k)...)
+				continue
+			}
+			if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.Schema != nil {
+					unknown = append(unknown, unknownSchemaFields(item, s.AdditionalProperties.Schema, path+"."+k)...)
+					continue
+				}
+				if s.AdditionalProperties.Allows {
+					continue
+				}
+			}
+			unknown = append(unknown, path+"."+k)
+		}
+	case []interface{}:
+		if s.Items == nil || s.Items.Schema == nil {
+			return nil
+		}
+		for i, item := range v {
+			unknown = append(unknown, unknownSchemaFields(item, s.Items.Schema, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,104 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listDataSourceSchema builds the schema shared by the plural data sources:
+// the list filters plus an `items` attribute whose spec has the same shape as
+// the matching resource.
+func listDataSourceSchema(objectName string, specSchema map[string]*schema.Schema) map[string]*schema.Schema {
+	s := listFilterSchema(objectName)
+	s["items"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Description: fmt.Sprintf("The %ss matching the filters.", objectName),
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"namespace": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"labels": {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"annotations": {
+					Type:     schema.TypeMap,
+					Computed: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"spec": {
+					Type:     schema.TypeList,
+					Computed: true,
+					Elem: &schema.Resource{
+						Schema: datasourceSchemaFromResourceSchema(specSchema),
+					},
+				},
+			},
+		},
+	}
+	return s
+}
+
+func listFilterSchema(objectName string) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"namespace": {
+			Type:        schema.TypeString,
+			Description: fmt.Sprintf("Namespace to list %ss from. Leave empty to list across all namespaces.", objectName),
+			Optional:    true,
+		},
+		"label_selector": {
+			Type:        schema.TypeString,
+			Description: "A selector to restrict the list of returned objects by their labels, e.g. `app=foo,tier!=db`.",
+			Optional:    true,
+		},
+		"field_selector": {
+			Type:        schema.TypeString,
+			Description: "A selector to restrict the list of returned objects by their fields, e.g. `metadata.name=foo`.",
+			Optional:    true,
+		},
+		"page_size": {
+			Type:         schema.TypeInt,
+			Description:  "Maximum number of objects requested per API call. Results are fetched page by page with continue tokens until the list is exhausted.",
+			Optional:     true,
+			Default:      500,
+			ValidateFunc: validatePositiveInteger,
+		},
+	}
+}
+
+func expandListOptions(d *schema.ResourceData) metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: d.Get("label_selector").(string),
+		FieldSelector: d.Get("field_selector").(string),
+		Limit:         int64(d.Get("page_size").(int)),
+	}
+}
+
+func buildListId(d *schema.ResourceData) string {
+	return strings.Join([]string{
+		d.Get("namespace").(string),
+		d.Get("label_selector").(string),
+		d.Get("field_selector").(string),
+	}, "/")
+}
+
+func flattenListItem(meta metav1.ObjectMeta, spec []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        meta.Name,
+		"namespace":   meta.Namespace,
+		"labels":      meta.Labels,
+		"annotations": meta.Annotations,
+		"spec":        spec,
+	}
+}
@@ -0,0 +1,56 @@
+package po
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func dataSourcePoPodMonitors() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: traceOperation(po_types.PodMonitorsKind, "read", dataSourcePoPodMonitorsRead),
+		Schema:      listDataSourceSchema("pod monitor", podMonitorSpecSchema()),
+	}
+}
+
+func dataSourcePoPodMonitorsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(KubeClientsets).MonitoringClientset()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	namespace := d.Get("namespace").(string)
+	opts := expandListOptions(d)
+
+	items := []interface{}{}
+	for {
+		log.Printf("[INFO] Listing pod monitors in namespace %q (continue=%q)", namespace, opts.Continue)
+		list, err := conn.MonitoringV1().PodMonitors(namespace).List(ctx, opts)
+		if err != nil {
+			log.Printf("[DEBUG] Received error: %#v", err)
+			return diag.FromErr(err)
+		}
+		for _, item := range list.Items {
+			spec, err := flattenPodMonitorSpec(item.Spec)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			items = append(items, flattenListItem(item.ObjectMeta, spec))
+		}
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+	log.Printf("[INFO] Received %d pod monitors", len(items))
+
+	d.SetId(buildListId(d))
+	err = d.Set("items", items)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package po
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func dataSourcePoProbes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: traceOperation(po_types.ProbesKind, "read", dataSourcePoProbesRead),
+		Schema:      listDataSourceSchema("probe", probeSpecSchema()),
+	}
+}
+
+func dataSourcePoProbesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(KubeClientsets).MonitoringClientset()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	namespace := d.Get("namespace").(string)
+	opts := expandListOptions(d)
+
+	items := []interface{}{}
+	for {
+		log.Printf("[INFO] Listing probes in namespace %q (continue=%q)", namespace, opts.Continue)
+		list, err := conn.MonitoringV1().Probes(namespace).List(ctx, opts)
+		if err != nil {
+			log.Printf("[DEBUG] Received error: %#v", err)
+			return diag.FromErr(err)
+		}
+		for _, item := range list.Items {
+			spec, err := flattenProbeSpec(item.Spec)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			items = append(items, flattenListItem(item.ObjectMeta, spec))
+		}
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+	log.Printf("[INFO] Received %d probes", len(items))
+
+	d.SetId(buildListId(d))
+	err = d.Set("items", items)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
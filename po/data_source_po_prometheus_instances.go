@@ -0,0 +1,141 @@
+package po
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func dataSourcePoPrometheusInstances() *schema.Resource {
+	s := listFilterSchema("Prometheus instance")
+	s["items"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "The Prometheus instances matching the filters, with the selectors they use to discover monitoring objects.",
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: prometheusInstanceSchema(),
+		},
+	}
+	return &schema.Resource{
+		ReadContext: traceOperation(po_types.PrometheusesKind, "read", dataSourcePoPrometheusInstancesRead),
+		Schema:      s,
+	}
+}
+
+func prometheusInstanceSchema() map[string]*schema.Schema {
+	selector := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Description: description,
+			Computed:    true,
+			Elem: &schema.Resource{
+				Schema: datasourceSchemaFromResourceSchema(labelSelectorFields(true)),
+			},
+		}
+	}
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"namespace": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"labels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"replicas": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"external_labels": {
+			Type:     schema.TypeMap,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"service_monitor_selector":           selector("ServiceMonitors selected for target discovery. Empty when the instance selects none."),
+		"service_monitor_namespace_selector": selector("Namespaces selected for ServiceMonitor discovery. Empty when only the instance's own namespace is checked."),
+		"pod_monitor_selector":               selector("PodMonitors selected for target discovery. Empty when the instance selects none."),
+		"probe_selector":                     selector("Probes selected for target discovery. Empty when the instance selects none."),
+		"rule_selector":                      selector("PrometheusRules selected for loading alerting/recording rules. Empty when the instance selects none."),
+		"rule_namespace_selector":            selector("Namespaces selected for PrometheusRule discovery. Empty when only the instance's own namespace is checked."),
+	}
+}
+
+func dataSourcePoPrometheusInstancesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(KubeClientsets).MonitoringClientset()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	namespace := d.Get("namespace").(string)
+	opts := expandListOptions(d)
+
+	items := []interface{}{}
+	for {
+		log.Printf("[INFO] Listing prometheus instances in namespace %q (continue=%q)", namespace, opts.Continue)
+		list, err := conn.MonitoringV1().Prometheuses(namespace).List(ctx, opts)
+		if err != nil {
+			log.Printf("[DEBUG] Received error: %#v", err)
+			return diag.FromErr(err)
+		}
+		for _, p := range list.Items {
+			items = append(items, flattenPrometheusInstance(p))
+		}
+		if list.Continue == "" {
+			break
+		}
+		opts.Continue = list.Continue
+	}
+	log.Printf("[INFO] Received %d prometheus instances", len(items))
+
+	d.SetId(buildListId(d))
+	err = d.Set("items", items)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func flattenPrometheusInstance(p *po_types.Prometheus) map[string]interface{} {
+	att := make(map[string]interface{})
+	att["name"] = p.Name
+	att["namespace"] = p.Namespace
+	att["labels"] = p.Labels
+	att["replicas"] = 1
+	if p.Spec.Replicas != nil {
+		att["replicas"] = int(*p.Spec.Replicas)
+	}
+	att["version"] = p.Spec.Version
+	att["external_labels"] = p.Spec.ExternalLabels
+
+	if p.Spec.ServiceMonitorSelector != nil {
+		att["service_monitor_selector"] = flattenLabelSelector(p.Spec.ServiceMonitorSelector)
+	}
+	if p.Spec.ServiceMonitorNamespaceSelector != nil {
+		att["service_monitor_namespace_selector"] = flattenLabelSelector(p.Spec.ServiceMonitorNamespaceSelector)
+	}
+	if p.Spec.PodMonitorSelector != nil {
+		att["pod_monitor_selector"] = flattenLabelSelector(p.Spec.PodMonitorSelector)
+	}
+	if p.Spec.ProbeSelector != nil {
+		att["probe_selector"] = flattenLabelSelector(p.Spec.ProbeSelector)
+	}
+	if p.Spec.RuleSelector != nil {
+		att["rule_selector"] = flattenLabelSelector(p.Spec.RuleSelector)
+	}
+	if p.Spec.RuleNamespaceSelector != nil {
+		att["rule_namespace_selector"] = flattenLabelSelector(p.Spec.RuleNamespaceSelector)
+	}
+	return att
+}
@@ -0,0 +1,64 @@
+package po
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+)
+
+func dataSourcePoServiceMonitor() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: traceOperation(po_types.ServiceMonitorsKind, "read", dataSourcePoServiceMonitorRead),
+		Schema: map[string]*schema.Schema{
+			"metadata": dataSourceNamespacedMetadataSchema("service monitor"),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Spec defines the specification of the desired behavior of the deployment. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#servicemonitorspec",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: datasourceSchemaFromResourceSchema(serviceMonitorSpecSchema()),
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePoServiceMonitorRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	conn, err := meta.(KubeClientsets).MonitoringClientset()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	namespace := d.Get("metadata.0.namespace").(string)
+	if namespace == "" {
+		namespace = meta.(KubeClientsets).MetadataConfig().DefaultNamespace
+	}
+	name := d.Get("metadata.0.name").(string)
+
+	log.Printf("[INFO] Reading service monitor %s", name)
+	sm, err := conn.MonitoringV1().ServiceMonitors(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("[DEBUG] Received error: %#v", err)
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Received service monitor: %s", objectSummary(sm))
+	d.SetId(buildId(sm.ObjectMeta))
+
+	err = d.Set("metadata", flattenDataSourceMetadata(sm.ObjectMeta))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	spec, err := flattenServiceMonitorSpec(sm.Spec, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = d.Set("spec", spec)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package po
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const redacted = "<redacted>"
+
+// redactedHeaders matches the dumped request and response headers that
+// carry credentials.
+var redactedHeaders = regexp.MustCompile(`(?mi)^((?:Authorization|Proxy-Authorization|Cookie|Set-Cookie):)[^\r\n]*`)
+
+// redactedQueryParams are query parameters that carry credentials.
+var redactedQueryParams = []string{
+	"access_token",
+	"token",
+	"id_token",
+}
+
+// redactedFields matches JSON string fields holding credentials, such as
+// the token, client certificate and key of exec plugin credentials and
+// kubeconfig auth infos.
+var redactedFields = regexp.MustCompile(`("(?:token|access_token|id_token|refresh_token|password|clientCertificateData|clientKeyData|client-certificate-data|client-key-data)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// redactingTransport logs requests and responses like the SDK logging
+// transport, with credentials masked.
+type redactingTransport struct {
+	name string
+	rt   http.RoundTripper
+}
+
+func newRedactingTransport(name string, rt http.RoundTripper) http.RoundTripper {
+	return &redactingTransport{name: name, rt: rt}
+}
+
+func (t *redactingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logged := req.Clone(req.Context())
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		logged.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	logged.URL = redactURL(logged.URL)
+	if reqData, err := httputil.DumpRequestOut(logged, true); err == nil {
+		log.Printf("[DEBUG] "+logReqMsg, t.name, redactDump(reqData))
+	} else {
+		log.Printf("[ERROR] %s API Request error: %s", t.name, err)
+	}
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if respData, err := httputil.DumpResponse(resp, true); err == nil {
+		log.Printf("[DEBUG] "+logRespMsg, t.name, redactDump(respData))
+	} else {
+		log.Printf("[ERROR] %s API Response error: %s", t.name, err)
+	}
+	return resp, nil
+}
+
+func redactURL(u *url.URL) *url.URL {
+	out := *u
+	if out.User != nil {
+		out.User = url.User(out.User.Username())
+	}
+	q := out.Query()
+	changed := false
+	for _, k := range redactedQueryParams {
+		if q.Get(k) != "" {
+			q.Set(k, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		out.RawQuery = q.Encode()
+	}
+	return &out
+}
+
+// redactDump masks credential headers and fields of a dumped request or
+// response, and pretty prints its JSON lines.
+func redactDump(b []byte) string {
+	b = redactedHeaders.ReplaceAll(b, []byte("$1 "+redacted))
+	parts := strings.Split(string(b), "\n")
+	for i, p := range parts {
+		p = redactedFields.ReplaceAllString(p, `$1"`+redacted+`"`)
+		if b := []byte(p); json.Valid(b) {
+			var out bytes.Buffer
+			json.Indent(&out, b, "", " ")
+			p = out.String()
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, "\n")
+}
+
+const logReqMsg = `%s API Request Details:
+---[ REQUEST ]---------------------------------------
+%s
+-----------------------------------------------------`
+
+const logRespMsg = `%s API Response Details:
+---[ RESPONSE ]--------------------------------------
+%s
+-----------------------------------------------------`
+
+// objectSummary describes obj for logs by kind, namespace, name and
+// resource version, instead of dumping specs that may embed secrets.
+func objectSummary(obj runtime.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	accessor, err := apimeta.Accessor(obj)
+	if err != nil {
+		return kind
+	}
+	return fmt.Sprintf("kind=%s namespace=%s name=%s resourceVersion=%s",
+		kind, accessor.GetNamespace(), accessor.GetName(), accessor.GetResourceVersion())
+}
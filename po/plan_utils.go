@@ -0,0 +1,205 @@
+package po
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// planChecks returns the plan-time checks shared by all resources: the
+// planned spec against the installed CRD, and the permissions the planned
+// change needs. Both need the API, so they are skipped while the provider
+// configuration depends on unknown values, and fail the plan when it is
+// invalid.
+func planChecks(gvr apimachineryschema.GroupVersionResource, expand func([]interface{}) (interface{}, error)) schema.CustomizeDiffFunc {
+	checks := customdiff.All(
+		validateSpecAgainstCRD(gvr, expand),
+		checkPermissions(gvr),
+	)
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if err := meta.(KubeClientsets).ConfigError(); err != nil {
+			if !meta.(KubeClientsets).ConfigUnknown() {
+				return err
+			}
+			log.Printf("[WARN] Skipping plan checks of %s: %s", gvr.Resource, err)
+			return nil
+		}
+		return checks(ctx, d, meta)
+	}
+}
+
+// appliedDefaultsSchema returns the computed attribute tracking the
+// provider defaults of the given metadata field on an object.
+func appliedDefaultsSchema(field string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: fmt.Sprintf("The provider default_%s the object carries, except those set in metadata. A change of the provider defaults shows here and is applied to the object.", field),
+	}
+}
+
+// planDefaults plans a change of applied_default_labels and
+// applied_default_annotations when the provider defaults differ from what
+// the object carries, so that adding or changing a default updates every
+// object rather than waiting for an unrelated change.
+func planDefaults(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if meta.(KubeClientsets).ConfigUnknown() {
+		return nil
+	}
+	mc := meta.(KubeClientsets).MetadataConfig()
+	for field, defaults := range map[string]map[string]string{
+		"labels":      mc.DefaultLabels,
+		"annotations": mc.DefaultAnnotations,
+	} {
+		key := "applied_default_" + field
+		if !d.NewValueKnown("metadata.0." + field) {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+			continue
+		}
+		planned := plannedDefaults(defaults, d.Get("metadata.0."+field).(map[string]interface{}))
+		if !sameStringMap(planned, d.Get(key).(map[string]interface{})) {
+			if err := d.SetNew(key, planned); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sameStringMap(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// unconfiguredRead keeps the state of an object as is, with a warning, when
+// it is refreshed while the provider configuration depends on unknown
+// values, so the plan does not show the object as gone. An invalid
+// configuration is an error. It returns nil when the configuration is
+// usable.
+func unconfiguredRead(d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	err := meta.(KubeClientsets).ConfigError()
+	if err == nil {
+		return nil
+	}
+	if !meta.(KubeClientsets).ConfigUnknown() || len(d.Get("metadata").([]interface{})) == 0 {
+		// Nothing to keep, e.g. on import.
+		return diag.FromErr(err)
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Skipping refresh of %s", d.Id()),
+			Detail:   err.Error(),
+		},
+	}
+}
+
+type plannedAccess struct {
+	verb      string
+	namespace string
+	name      string
+}
+
+// checkPermissions returns a CustomizeDiffFunc that asks the API server,
+// through SelfSubjectAccessReviews, whether the provider identity may make
+// the planned change, so missing RBAC rules fail the plan instead of
+// leaving an apply half done.
+func checkPermissions(gvr apimachineryschema.GroupVersionResource) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !meta.(KubeClientsets).CheckPermissions() {
+			return nil
+		}
+		if plannedNamespaceUnknown(ctx) {
+			log.Printf("[DEBUG] Skipping permission checks of %s: namespace is unknown until apply", gvr.Resource)
+			return nil
+		}
+		accesses := plannedAccesses(d, meta)
+		if len(accesses) == 0 {
+			return nil
+		}
+		conn, err := meta.(KubeClientsets).MainClientset()
+		if err != nil {
+			return err
+		}
+		missing := make([]string, 0)
+		for _, a := range accesses {
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: a.namespace,
+						Verb:      a.verb,
+						Group:     gvr.Group,
+						Version:   gvr.Version,
+						Resource:  gvr.Resource,
+						Name:      a.name,
+					},
+				},
+			}
+			out, err := conn.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("Failed to check permission to %s %s.%s in namespace %q: %s", a.verb, gvr.Resource, gvr.Group, a.namespace, err)
+			}
+			if out.Status.Allowed {
+				continue
+			}
+			m := fmt.Sprintf("%s %s.%s in namespace %q", a.verb, gvr.Resource, gvr.Group, a.namespace)
+			if out.Status.Reason != "" {
+				m += fmt.Sprintf(" (%s)", out.Status.Reason)
+			}
+			missing = append(missing, m)
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("Missing RBAC permissions: %s", strings.Join(missing, "; "))
+		}
+		return nil
+	}
+}
+
+// plannedAccesses returns the verbs the planned change needs. Server-side
+// apply creates objects with create followed by patch, and a change of name
+// or namespace replaces the object. Terraform plans a plain destroy without
+// calling CustomizeDiff, so delete is only checked for replacements.
+func plannedAccesses(d *schema.ResourceDiff, meta interface{}) []plannedAccess {
+	namespace := d.Get("metadata.0.namespace").(string)
+	if namespace == "" {
+		namespace = meta.(KubeClientsets).MetadataConfig().DefaultNamespace
+	}
+	name := d.Get("metadata.0.name").(string)
+	creates := []plannedAccess{{verb: "create", namespace: namespace}}
+	if meta.(KubeClientsets).ApplyConfig().ServerSide {
+		creates = append(creates, plannedAccess{verb: "patch", namespace: namespace, name: name})
+	}
+
+	if d.Id() == "" {
+		return creates
+	}
+	oldNamespace, oldName, err := idParts(d.Id())
+	if err != nil {
+		return nil
+	}
+	if d.HasChange("metadata.0.name") || d.HasChange("metadata.0.namespace") {
+		return append([]plannedAccess{{verb: "delete", namespace: oldNamespace, name: oldName}}, creates...)
+	}
+	if d.HasChange("metadata") || d.HasChange("spec") {
+		return []plannedAccess{{verb: "patch", namespace: namespace, name: name}}
+	}
+	return nil
+}
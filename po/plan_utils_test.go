@@ -0,0 +1,69 @@
+package po
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// metadataClientsets serves the metadata configuration only.
+type metadataClientsets struct {
+	KubeClientsets
+	mc MetadataConfig
+}
+
+func (m metadataClientsets) MetadataConfig() MetadataConfig { return m.mc }
+func (m metadataClientsets) ConfigUnknown() bool            { return false }
+
+func TestPlanDefaultsAppliesNewDefaults(t *testing.T) {
+	r := resourcePoServiceMonitor()
+	r.CustomizeDiff = planDefaults
+	state := &terraform.InstanceState{
+		ID: "default/example",
+		Attributes: map[string]string{
+			"id":                            "default/example",
+			"metadata.#":                    "1",
+			"metadata.0.name":               "example",
+			"metadata.0.namespace":          "default",
+			"metadata.0.labels.%":           "1",
+			"metadata.0.labels.app":         "example",
+			"spec.#":                        "1",
+			"applied_default_labels.%":      "0",
+			"applied_default_annotations.%": "0",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"metadata": []interface{}{map[string]interface{}{
+			"name":      "example",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "example"},
+		}},
+		"spec": []interface{}{map[string]interface{}{}},
+	})
+	meta := metadataClientsets{mc: MetadataConfig{DefaultLabels: map[string]string{"team": "observability", "app": "default"}}}
+
+	diff, err := r.Diff(context.Background(), state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		t.Fatal("expected a new default label to be planned")
+	}
+	if a, ok := diff.Attributes["applied_default_labels.team"]; !ok || a.New != "observability" {
+		t.Fatalf("expected applied_default_labels.team to be planned, got %#v", diff.Attributes)
+	}
+	if _, ok := diff.Attributes["applied_default_labels.app"]; ok {
+		t.Fatalf("expected the label set in metadata to take precedence over its default, got %#v", diff.Attributes)
+	}
+
+	state.Attributes["applied_default_labels.%"] = "1"
+	state.Attributes["applied_default_labels.team"] = "observability"
+	diff, err = r.Diff(context.Background(), state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Fatalf("expected no change once the default is applied, got %#v", diff.Attributes)
+	}
+}
@@ -4,23 +4,34 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/go-homedir"
 	monitoring "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	restclient "k8s.io/client-go/rest"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/flowcontrol"
 	aggregator "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 )
 
@@ -73,7 +84,7 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeList,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				Optional:    true,
-				Description: "A list of paths to kube config files. Can be set with KUBE_CONFIG_PATHS environment variable.",
+				Description: "A list of paths to kube config files. Can be set with KUBE_CONFIG_PATHS environment variable. Without config_path or config_paths, KUBECONFIG is used unless host, credentials or kubeconfig_raw are set.",
 			},
 			"config_path": {
 				Type:          schema.TypeString,
@@ -82,6 +93,27 @@ func Provider() *schema.Provider {
 				Description:   "Path to the kube config file. Can be set with KUBE_CONFIG_PATH.",
 				ConflictsWith: []string{"config_paths"},
 			},
+			"kubeconfig_raw": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("KUBE_CONFIG_RAW", ""),
+				Description:   "Content of a kube config file. Can be set with KUBE_CONFIG_RAW. config_context and the other overrides apply to it like to a file.",
+				ConflictsWith: []string{"config_path", "config_paths"},
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("KUBE_PROXY_URL", nil),
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "URL of the proxy used for all requests to the Kubernetes master, e.g. `http://proxy.example.com:3128`. Can be set with KUBE_PROXY_URL.",
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUBE_TLS_SERVER_NAME", ""),
+				Description: "Server name used to verify the certificate of the Kubernetes master, when it differs from the host name in `host`. Can be set with KUBE_TLS_SERVER_NAME.",
+			},
 			"config_context": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -105,6 +137,56 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("KUBE_TOKEN", ""),
 				Description: "Token to authenticate an service account",
 			},
+			"impersonate": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Identity to impersonate for every request to the Kubernetes master. The authenticated identity needs the impersonate permission on it.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Username to impersonate, e.g. `system:serviceaccount:team-a:terraform`.",
+						},
+						"uid": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "UID to impersonate. Requires Kubernetes 1.22 or newer.",
+						},
+						"groups": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Groups to impersonate.",
+						},
+						"extra": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Extra user information to impersonate.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"key": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"values": {
+										Type:     schema.TypeList,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"in_cluster": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KUBE_IN_CLUSTER", false),
+				Description: "Authenticate with the service account of the pod Terraform runs in. Used automatically when the provider runs inside a cluster and no connection argument (host, credentials, kube config or context) is set, nor unknown during plan.",
+			},
 			"exec": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -133,9 +215,173 @@ func Provider() *schema.Provider {
 				},
 				Description: "",
 			},
+			"default_labels": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateLabels,
+				Description:  "Labels added to every object managed by the provider. Labels set on a resource take precedence. A change of the defaults is planned on every resource as a change of applied_default_labels, and applied to the objects.",
+			},
+			"default_annotations": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				ValidateFunc: validateAnnotations,
+				Description:  "Annotations added to every object managed by the provider. Annotations set on a resource take precedence. A change of the defaults is planned on every resource as a change of applied_default_annotations, and applied to the objects.",
+			},
+			"ignore_labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+				Description: "Regular expressions matching label keys that are managed outside of Terraform, e.g. `^app\\.kubernetes\\.io/managed-by$`. Matching keys are kept out of state unless they are set on the resource.",
+			},
+			"ignore_annotations": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsValidRegExp,
+				},
+				Description: "Regular expressions matching annotation keys that are managed outside of Terraform, e.g. `^argocd\\.argoproj\\.io/` or `^meta\\.helm\\.sh/`. Matching keys are kept out of state unless they are set on the resource.",
+			},
+			"apply_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      applyModeJSONPatch,
+				ValidateFunc: validation.StringInSlice([]string{applyModeJSONPatch, applyModeServerSide}, false),
+				Description:  "How objects are written. `json-patch` replaces the whole spec on update; `server-side` updates objects with server-side apply, leaving fields owned by other managers alone, and applies new objects right after creating them. In both modes, creating an object that already exists fails; import it instead.",
+			},
+			"field_manager": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "terraform-provider-po",
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Field manager name used for server-side apply.",
+			},
+			"force_conflicts": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take ownership of fields managed by other field managers when using server-side apply.",
+			},
+			"validate_crd_schema": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Validate planned objects against the openAPIV3Schema of the prometheus-operator CRDs installed in the cluster, reporting fields the installed version would prune. Needs cluster-wide `get` on `customresourcedefinitions`; the check is skipped with a warning in the logs when that is forbidden.",
+			},
+			"qps": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Description: "Maximum sustained queries per second to the API server. Defaults to the client-go default of 5.",
+			},
+			"burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateNonNegativeInteger,
+				Description:  "Maximum burst of queries to the API server. Defaults to the client-go default of 10.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of a single request to the API server, e.g. `30s`. No timeout by default.",
+			},
+			"retry_max_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validatePositiveInteger,
+				Description:  "Maximum number of attempts for requests failing with transient errors (429, 5xx, broken connections) and for updates failing with conflicts. Responses carrying Retry-After are retried by the Kubernetes client itself, up to 10 times, and do not count against this limit. Set to 1 to disable retries.",
+			},
+			"retry_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "500ms",
+				ValidateFunc: validateDuration,
+				Description:  "Backoff before the first retry. It doubles with every attempt.",
+			},
+			"retry_max_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "30s",
+				ValidateFunc: validateDuration,
+				Description:  "Upper bound of the backoff between retries.",
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file to which every create, patch and delete of a monitoring object is appended as a JSON line, whether it succeeds or fails. Requests fail when they cannot be audited. The user recorded is the kubeconfig user name, or \"in-cluster\", not the identity the API server authenticates.",
+			},
+			"tracing": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "OpenTelemetry tracing of the resource operations and of the requests sent to the Kubernetes API.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"otlp_endpoint": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsURLWithScheme([]string{"http", "https"}),
+							AtLeastOneOf: []string{"tracing.0.otlp_endpoint", "tracing.0.file_path"},
+							Description:  "URL of an OTLP/HTTP collector, e.g. `http://localhost:4318`. Spans are posted to `/v1/traces` unless the URL has a path.",
+						},
+						"otlp_headers": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Sensitive:   true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Headers sent with every export to the OTLP endpoint.",
+						},
+						"file_path": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							AtLeastOneOf: []string{"tracing.0.otlp_endpoint", "tracing.0.file_path"},
+							Description:  "Path of a file to which finished spans are appended, one JSON document per line.",
+						},
+					},
+				},
+			},
+			"check_permissions": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Check with SelfSubjectAccessReviews during plan that the provider identity may create, patch or delete the planned objects. Terraform does not consult the provider when planning a plain destroy, so deletes are only checked when an object is replaced. Objects whose namespace is unknown until apply are not checked.",
+			},
+			"list_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read objects from a single LIST per resource type and namespace instead of one GET per object. Speeds up the refresh of many objects at the cost of listing whole namespaces.",
+			},
+			"check_resource_version": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Guard updates with the resource_version recorded in state. Updates fail instead of overwriting objects that changed since the last refresh.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"po_service_monitor": resourcePoServiceMonitor(),
+			"po_service_monitor":     resourcePoServiceMonitor(),
+			"po_pod_monitor":         resourcePoPodMonitor(),
+			"po_prometheus_rule":     resourcePoPrometheusRule(),
+			"po_probe":               resourcePoProbe(),
+			"po_prometheus":          resourcePoPrometheus(),
+			"po_alertmanager":        resourcePoAlertmanager(),
+			"po_alertmanager_config": resourcePoAlertmanagerConfig(),
+			"po_thanos_ruler":        resourcePoThanosRuler(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"po_service_monitor":      dataSourcePoServiceMonitor(),
+			"po_service_monitors":     dataSourcePoServiceMonitors(),
+			"po_pod_monitors":         dataSourcePoPodMonitors(),
+			"po_prometheus_rules":     dataSourcePoPrometheusRules(),
+			"po_probes":               dataSourcePoProbes(),
+			"po_prometheus_instances": dataSourcePoPrometheusInstances(),
 		},
 	}
 	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -144,99 +390,493 @@ func Provider() *schema.Provider {
 	return p
 }
 
+// unknownArgsKey holds, in the configure context, the provider arguments
+// whose values are unknown.
+type unknownArgsKey struct{}
+
+// GRPCProviderServer serves Provider over gRPC. helper/schema reads unknown
+// provider arguments as empty values, so the raw configuration is checked
+// first and the unknown arguments are passed on to providerConfigure
+// through the context. Plans are handled the same way for the resources'
+// metadata.namespace.
+func GRPCProviderServer() tfprotov5.ProviderServer {
+	p := Provider()
+	return &providerServer{
+		ProviderServer: schema.NewGRPCProviderServer(p),
+		provider:       p,
+	}
+}
+
+type providerServer struct {
+	tfprotov5.ProviderServer
+	provider *schema.Provider
+}
+
+func (s *providerServer) ConfigureProvider(ctx context.Context, req *tfprotov5.ConfigureProviderRequest) (*tfprotov5.ConfigureProviderResponse, error) {
+	if req.Config != nil {
+		ty := schema.InternalMap(s.provider.Schema).CoreConfigSchema().ImpliedType()
+		if v, err := msgpack.Unmarshal(req.Config.MsgPack, ty); err == nil && !v.IsWhollyKnown() {
+			unknown := make(map[string]bool)
+			for k, av := range v.AsValueMap() {
+				if !av.IsWhollyKnown() {
+					unknown[k] = true
+				}
+			}
+			ctx = context.WithValue(ctx, unknownArgsKey{}, unknown)
+		}
+	}
+	return s.ProviderServer.ConfigureProvider(ctx, req)
+}
+
+// unknownArgs returns the provider arguments that were unknown when the
+// provider was configured.
+func unknownArgs(ctx context.Context) map[string]bool {
+	unknown, _ := ctx.Value(unknownArgsKey{}).(map[string]bool)
+	return unknown
+}
+
+// unknownNamespaceKey marks, in the plan context, a resource whose
+// metadata.namespace is unknown. helper/schema plans an unset namespace as
+// computed too, so the raw configuration is the only way to tell them apart.
+type unknownNamespaceKey struct{}
+
+func (s *providerServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	if r, ok := s.provider.ResourcesMap[req.TypeName]; ok && req.Config != nil {
+		ty := r.CoreConfigSchema().ImpliedType()
+		if v, err := msgpack.Unmarshal(req.Config.MsgPack, ty); err == nil && namespaceUnknown(v) {
+			ctx = context.WithValue(ctx, unknownNamespaceKey{}, true)
+		}
+	}
+	return s.ProviderServer.PlanResourceChange(ctx, req)
+}
+
+func namespaceUnknown(v cty.Value) bool {
+	if v.IsNull() || !v.Type().IsObjectType() || !v.Type().HasAttribute("metadata") {
+		return false
+	}
+	md := v.GetAttr("metadata")
+	if !md.IsKnown() {
+		return true
+	}
+	if md.IsNull() || !md.CanIterateElements() || md.LengthInt() == 0 {
+		return false
+	}
+	m := md.Index(cty.NumberIntVal(0))
+	return m.Type().IsObjectType() && m.Type().HasAttribute("namespace") && !m.GetAttr("namespace").IsKnown()
+}
+
+// plannedNamespaceUnknown reports whether the planned resource's namespace
+// depends on values unknown until apply.
+func plannedNamespaceUnknown(ctx context.Context) bool {
+	unknown, _ := ctx.Value(unknownNamespaceKey{}).(bool)
+	return unknown
+}
+
 type KubeClientsets interface {
 	MainClientset() (*kubernetes.Clientset, error)
 	AggregatorClientset() (*aggregator.Clientset, error)
 	MonitoringClientset() (*monitoring.Clientset, error)
+	ExtensionsClientset() (*apiextensions.Clientset, error)
+	MetadataConfig() MetadataConfig
+	ApplyConfig() ApplyConfig
+	ValidateCRDSchema() bool
+	RetryConfig() RetryConfig
+	ListCache() *listCache
+	CRDSchemaCache() *crdSchemaCache
+	CheckPermissions() bool
+	ConfigError() error
+	ConfigUnknown() bool
+	Tracing() *Tracing
+}
+
+// MetadataConfig holds the provider-wide metadata settings: the labels and
+// annotations merged into the metadata of every object it manages, the key
+// patterns that are kept out of state, and the namespace of objects that do
+// not set one.
+type MetadataConfig struct {
+	DefaultLabels      map[string]string
+	DefaultAnnotations map[string]string
+	IgnoreLabels       []*regexp.Regexp
+	IgnoreAnnotations  []*regexp.Regexp
+	DefaultNamespace   string
+}
+
+// ApplyConfig holds the provider settings for how objects are written.
+type ApplyConfig struct {
+	ServerSide           bool
+	FieldManager         string
+	ForceConflicts       bool
+	CheckResourceVersion bool
 }
 
 type kubeClientsets struct {
+	mu sync.Mutex
+
 	config              *restclient.Config
+	configErr           error
+	configUnknown       bool
+	sharedConfig        *restclient.Config
 	mainClientset       *kubernetes.Clientset
 	aggregatorClientset *aggregator.Clientset
 	monitoringClientset *monitoring.Clientset
+	extensionsClientset *apiextensions.Clientset
+	metadataConfig      MetadataConfig
+	applyConfig         ApplyConfig
+	validateCRDSchema   bool
+	retryConfig         RetryConfig
+	listCache           *listCache
+	crdSchemaCache      *crdSchemaCache
+	checkPermissions    bool
+	tracing             *Tracing
 
 	configData *schema.ResourceData
 }
 
-func (k kubeClientsets) MainClientset() (*kubernetes.Clientset, error) {
+func (k *kubeClientsets) MainClientset() (*kubernetes.Clientset, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	if k.mainClientset != nil {
 		return k.mainClientset, nil
 	}
 
-	if k.config != nil {
-		kc, err := kubernetes.NewForConfig(k.config)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to configure client: %s", err)
-		}
-		k.mainClientset = kc
+	cfg, err := k.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	kc, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure client: %s", err)
 	}
+	k.mainClientset = kc
 	return k.mainClientset, nil
 }
 
-func (k kubeClientsets) AggregatorClientset() (*aggregator.Clientset, error) {
+func (k *kubeClientsets) AggregatorClientset() (*aggregator.Clientset, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	if k.aggregatorClientset != nil {
 		return k.aggregatorClientset, nil
 	}
-	if k.config != nil {
-		ac, err := aggregator.NewForConfig(k.config)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to configure client: %s", err)
-		}
-		k.aggregatorClientset = ac
+	cfg, err := k.restConfig()
+	if err != nil {
+		return nil, err
 	}
+	ac, err := aggregator.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure client: %s", err)
+	}
+	k.aggregatorClientset = ac
 	return k.aggregatorClientset, nil
 }
 
-func (k kubeClientsets) MonitoringClientset() (*monitoring.Clientset, error) {
+func (k *kubeClientsets) MonitoringClientset() (*monitoring.Clientset, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	if k.monitoringClientset != nil {
 		return k.monitoringClientset, nil
 	}
 
-	if k.config != nil {
-		mc, err := monitoring.NewForConfig(k.config)
+	cfg, err := k.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	mc, err := monitoring.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure client: %s", err)
+	}
+	k.monitoringClientset = mc
+	return k.monitoringClientset, nil
+}
+
+func (k *kubeClientsets) ExtensionsClientset() (*apiextensions.Clientset, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.extensionsClientset != nil {
+		return k.extensionsClientset, nil
+	}
+
+	cfg, err := k.restConfig()
+	if err != nil {
+		return nil, err
+	}
+	ec, err := apiextensions.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure client: %s", err)
+	}
+	k.extensionsClientset = ec
+	return k.extensionsClientset, nil
+}
+
+// restConfig returns the configuration the clientsets are built from,
+// creating the shared transport on first use. Callers must hold k.mu.
+func (k *kubeClientsets) restConfig() (*restclient.Config, error) {
+	if err := k.ConfigError(); err != nil {
+		return nil, err
+	}
+	if k.sharedConfig == nil {
+		shared, err := sharedConfig(k.config)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to configure client: %s", err)
+			return nil, err
 		}
-		k.monitoringClientset = mc
+		k.sharedConfig = shared
 	}
-	return k.monitoringClientset, nil
+	return k.sharedConfig, nil
+}
+
+// ConfigError returns the error that made the connection settings of the
+// provider unusable, or nil when clients can be built.
+func (k *kubeClientsets) ConfigError() error {
+	if k.configErr == nil {
+		return nil
+	}
+	if k.configUnknown {
+		return fmt.Errorf("The provider configuration depends on values that are not known yet, so the Kubernetes API cannot be reached: %s. "+
+			"This is expected during plan when the provider configuration depends on resources created in the same apply.", k.configErr)
+	}
+	return fmt.Errorf("The provider configuration is invalid, so the Kubernetes API cannot be reached: %s", k.configErr)
+}
+
+// ConfigUnknown reports whether some provider arguments were unknown when
+// the provider was configured, which happens during plan when they depend
+// on resources that are not created yet.
+func (k *kubeClientsets) ConfigUnknown() bool {
+	return k.configUnknown
+}
+
+func (k *kubeClientsets) MetadataConfig() MetadataConfig {
+	return k.metadataConfig
+}
+
+func (k *kubeClientsets) ApplyConfig() ApplyConfig {
+	return k.applyConfig
+}
+
+func (k *kubeClientsets) ValidateCRDSchema() bool {
+	return k.validateCRDSchema
+}
+
+func (k *kubeClientsets) RetryConfig() RetryConfig {
+	return k.retryConfig
+}
+
+func (k *kubeClientsets) ListCache() *listCache {
+	return k.listCache
+}
+
+func (k *kubeClientsets) CRDSchemaCache() *crdSchemaCache {
+	return k.crdSchemaCache
+}
+
+func (k *kubeClientsets) CheckPermissions() bool {
+	return k.checkPermissions
+}
+
+func (k *kubeClientsets) Tracing() *Tracing {
+	return k.tracing
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVersion string) (interface{}, diag.Diagnostics) {
-	// Config initialization
-	cfg, err := initializeConfiguration(d)
+	// Config initialization. An unusable configuration is not an error
+	// yet: it may depend on values only known after apply, e.g. a cluster
+	// created in the same run. It is kept and reported by the first
+	// operation that needs the API; plans skip API calls only when some
+	// arguments are unknown.
+	unknown := unknownArgs(ctx)
+	cfg, cctx, configErr := initializeConfiguration(d, unknown)
+	if configErr != nil {
+		log.Printf("[WARN] Invalid provider configuration was supplied. Provider operations needing the API will fail: %v", configErr)
+	}
+	if cctx.Namespace == "" {
+		cctx.Namespace = "default"
+	}
+
+	retryConfig, err := expandRetryConfig(d)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
-	if cfg == nil {
-		// This is a TEMPORARY measure to work around https://github.com/hashicorp/terraform/issues/24055
-		// IMPORTANT: this will NOT enable a workaround of issue: https://github.com/hashicorp/terraform/issues/4149
-		// IMPORTANT: if the supplied configuration is incomplete or invalid
-		///IMPORTANT: provider operations will fail or attempt to connect to localhost endpoints
-		cfg = &restclient.Config{}
+	tracing, err := expandTracing(ctx, d.Get("tracing").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
 	}
 
-	cfg.UserAgent = fmt.Sprintf("HashiCorp/1.0 Terraform/%s", terraformVersion)
+	if cfg != nil {
+		cfg.UserAgent = fmt.Sprintf("HashiCorp/1.0 Terraform/%s", terraformVersion)
+
+		if v, ok := d.GetOk("qps"); ok {
+			cfg.QPS = float32(v.(float64))
+		}
+		if v, ok := d.GetOk("burst"); ok {
+			cfg.Burst = v.(int)
+		}
+		if v, ok := d.GetOk("request_timeout"); ok {
+			timeout, err := time.ParseDuration(v.(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			cfg.Timeout = timeout
+		}
+
+		if logging.IsDebugOrHigher() {
+			log.Printf("[DEBUG] Enabling HTTP requests/responses tracing")
+			cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+				return newRedactingTransport("po", rt)
+			})
+		}
+
+		if v, ok := d.GetOk("audit_log_path"); ok {
+			path, err := homedir.Expand(v.(string))
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			if err := checkAuditLog(path); err != nil {
+				return nil, diag.Errorf("Failed to open audit_log_path: %s", err)
+			}
+			cfg.Wrap(newAuditTransport(&auditLog{
+				path:        path,
+				user:        cctx.User,
+				impersonate: cfg.Impersonate.UserName,
+				server:      cfg.Host,
+			}))
+		}
+
+		// Retries wrap the tracing and audit transports so that every
+		// attempt is logged and audited.
+		cfg.Wrap(newRetryRoundTripper(retryConfig))
 
-	if logging.IsDebugOrHigher() {
-		log.Printf("[DEBUG] Enabling HTTP requests/responses tracing")
-		cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
-			return logging.NewTransport("po", rt)
+		if tracing != nil {
+			cfg.Wrap(newSpanTransport(tracing.Tracer()))
 		}
 	}
 
-	m := kubeClientsets{
+	ignoreLabels, err := expandRegexpList(d.Get("ignore_labels").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	ignoreAnnotations, err := expandRegexpList(d.Get("ignore_annotations").([]interface{}))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	m := &kubeClientsets{
 		config:              cfg,
+		configErr:           configErr,
+		configUnknown:       len(unknown) > 0,
 		mainClientset:       nil,
 		aggregatorClientset: nil,
 		monitoringClientset: nil,
-		configData:          d,
+		extensionsClientset: nil,
+		metadataConfig: MetadataConfig{
+			DefaultLabels:      expandStringMap(d.Get("default_labels").(map[string]interface{})),
+			DefaultAnnotations: expandStringMap(d.Get("default_annotations").(map[string]interface{})),
+			IgnoreLabels:       ignoreLabels,
+			IgnoreAnnotations:  ignoreAnnotations,
+			DefaultNamespace:   cctx.Namespace,
+		},
+		applyConfig: ApplyConfig{
+			ServerSide:           d.Get("apply_mode").(string) == applyModeServerSide,
+			FieldManager:         d.Get("field_manager").(string),
+			ForceConflicts:       d.Get("force_conflicts").(bool),
+			CheckResourceVersion: d.Get("check_resource_version").(bool),
+		},
+		validateCRDSchema: d.Get("validate_crd_schema").(bool),
+		crdSchemaCache:    newCRDSchemaCache(),
+		retryConfig:       retryConfig,
+		checkPermissions:  d.Get("check_permissions").(bool),
+		tracing:           tracing,
+		configData:        d,
+	}
+	if d.Get("list_cache").(bool) {
+		m.listCache = newListCache()
 	}
 	return m, diag.Diagnostics{}
 }
 
-func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error) {
+// sharedConfig returns a copy of cfg that reuses one transport and one rate
+// limiter, so every clientset built from it shares connections and the
+// qps/burst budget.
+func sharedConfig(cfg *restclient.Config) (*restclient.Config, error) {
+	rt, err := restclient.TransportFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure transport: %s", err)
+	}
+	qps, burst := cfg.QPS, cfg.Burst
+	if qps == 0 {
+		qps = restclient.DefaultQPS
+	}
+	if burst == 0 {
+		burst = restclient.DefaultBurst
+	}
+	shared := restclient.AnonymousClientConfig(cfg)
+	// TLS and credentials are already part of the transport.
+	shared.TLSClientConfig = restclient.TLSClientConfig{}
+	shared.Transport = rt
+	shared.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	return shared, nil
+}
+
+func expandRetryConfig(d *schema.ResourceData) (RetryConfig, error) {
+	backoff, err := time.ParseDuration(d.Get("retry_backoff").(string))
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	maxBackoff, err := time.ParseDuration(d.Get("retry_max_backoff").(string))
+	if err != nil {
+		return RetryConfig{}, err
+	}
+	return RetryConfig{
+		MaxAttempts: d.Get("retry_max_attempts").(int),
+		Backoff:     backoff,
+		MaxBackoff:  maxBackoff,
+	}, nil
+}
+
+func expandRegexpList(l []interface{}) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(l))
+	for _, v := range l {
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse pattern %q: %s", v, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// configContext holds what the provider takes from the kubeconfig context
+// besides the client configuration.
+type configContext struct {
+	Namespace string
+	User      string
+}
+
+// credentialArgs are the provider arguments that set the API server and
+// credentials without a kube config file.
+var credentialArgs = []string{
+	"host", "username", "password", "client_certificate", "client_key", "cluster_ca_certificate", "token", "exec", "kubeconfig_raw",
+}
+
+// connectionArgs are the provider arguments that tell how to reach and
+// authenticate to the API server.
+var connectionArgs = append([]string{
+	"config_path", "config_paths", "config_context", "config_context_auth_info", "config_context_cluster",
+}, credentialArgs...)
+
+// noArgs reports whether none of args is set, or unknown because it
+// depends on a resource not created yet.
+func noArgs(d *schema.ResourceData, unknown map[string]bool, args []string) bool {
+	for _, k := range args {
+		if _, ok := d.GetOk(k); ok || unknown[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func initializeConfiguration(d *schema.ResourceData, unknown map[string]bool) (*restclient.Config, configContext, error) {
 	overrides := &clientcmd.ConfigOverrides{}
 	loader := &clientcmd.ClientConfigLoadingRules{}
 
@@ -252,6 +892,29 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 		// NOTE we have to do this here because the schema
 		// does not yet allow you to set a default for a TypeList
 		configPaths = filepath.SplitList(v)
+	} else if v := os.Getenv("KUBECONFIG"); v != "" && noArgs(d, unknown, credentialArgs) {
+		// Explicit credentials are not merged with the current context of
+		// whatever kube config the environment points at.
+		configPaths = filepath.SplitList(v)
+	}
+
+	rawConfig := d.Get("kubeconfig_raw").(string)
+
+	inCluster := d.Get("in_cluster").(bool)
+	if !inCluster && len(configPaths) == 0 && noArgs(d, unknown, connectionArgs) && os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		log.Printf("[DEBUG] No connection settings configured, falling back to in-cluster configuration")
+		inCluster = true
+	}
+	impersonate := expandImpersonation(d.Get("impersonate").([]interface{}))
+
+	if inCluster {
+		cfg, err := restclient.InClusterConfig()
+		if err != nil {
+			return nil, configContext{}, fmt.Errorf("Failed to load in-cluster configuration: %s", err)
+		}
+		cfg.Impersonate = impersonate
+		wrapImpersonateUID(cfg, d.Get("impersonate").([]interface{}))
+		return cfg, configContext{Namespace: inClusterNamespace(), User: "in-cluster"}, nil
 	}
 
 	if len(configPaths) > 0 {
@@ -259,7 +922,7 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 		for _, p := range configPaths {
 			path, err := homedir.Expand(p)
 			if err != nil {
-				return nil, err
+				return nil, configContext{}, err
 			}
 
 			log.Printf("[DEBUG] Using kubeconfig: %s", path)
@@ -271,7 +934,9 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 		} else {
 			loader.Precedence = expandedPaths
 		}
+	}
 
+	if len(configPaths) > 0 || rawConfig != "" {
 		ctxSuffix := "; default context"
 
 		kubectx, ctxOk := d.GetOk("config_context")
@@ -305,6 +970,12 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 	if v, ok := d.GetOk("cluster_ca_certificate"); ok {
 		overrides.ClusterInfo.CertificateAuthorityData = bytes.NewBufferString(v.(string)).Bytes()
 	}
+	if v, ok := d.GetOk("tls_server_name"); ok {
+		overrides.ClusterInfo.TLSServerName = v.(string)
+	}
+	if v, ok := d.GetOk("proxy_url"); ok {
+		overrides.ClusterInfo.ProxyURL = v.(string)
+	}
 	if v, ok := d.GetOk("client_certificate"); ok {
 		overrides.AuthInfo.ClientCertificateData = bytes.NewBufferString(v.(string)).Bytes()
 	}
@@ -318,7 +989,7 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 		defaultTLS := hasCA || hasCert || overrides.ClusterInfo.InsecureSkipTLSVerify
 		host, _, err := restclient.DefaultServerURL(v.(string), "", apimachineryschema.GroupVersion{}, defaultTLS)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to parse host: %s", err)
+			return nil, configContext{}, fmt.Errorf("Failed to parse host: %s", err)
 		}
 
 		overrides.ClusterInfo.Server = host.String()
@@ -346,17 +1017,111 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 				exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: kk, Value: vv.(string)})
 			}
 		} else {
-			return nil, fmt.Errorf("Failed to parse exec")
+			return nil, configContext{}, fmt.Errorf("Failed to parse exec")
 		}
 		overrides.AuthInfo.Exec = exec
 	}
 
-	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	if impersonate.UserName != "" {
+		log.Printf("[DEBUG] Impersonating user %q, groups %v", impersonate.UserName, impersonate.Groups)
+		overrides.AuthInfo.Impersonate = impersonate.UserName
+		overrides.AuthInfo.ImpersonateGroups = impersonate.Groups
+		overrides.AuthInfo.ImpersonateUserExtra = impersonate.Extra
+	}
+
+	var cc clientcmd.ClientConfig
+	if rawConfig != "" {
+		log.Printf("[DEBUG] Using kubeconfig from kubeconfig_raw")
+		rc, err := clientcmd.Load([]byte(rawConfig))
+		if err != nil {
+			return nil, configContext{}, fmt.Errorf("Failed to parse kubeconfig_raw: %s", err)
+		}
+		cc = clientcmd.NewNonInteractiveClientConfig(*rc, overrides.CurrentContext, overrides, nil)
+	} else {
+		cc = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	}
+	cctx := configContext{Namespace: "default"}
+	if namespace, _, err := cc.Namespace(); err == nil && namespace != "" {
+		cctx.Namespace = namespace
+	}
+	log.Printf("[DEBUG] Using default namespace: %s", cctx.Namespace)
+	cctx.User = configUser(cc, overrides)
+
 	cfg, err := cc.ClientConfig()
 	if err != nil {
-		log.Printf("[WARN] Invalid provider configuration was supplied. Provider operations likely to fail: %v", err)
-		return nil, nil
+		return nil, cctx, err
+	}
+
+	wrapImpersonateUID(cfg, d.Get("impersonate").([]interface{}))
+	return cfg, cctx, nil
+}
+
+func expandImpersonation(l []interface{}) restclient.ImpersonationConfig {
+	ic := restclient.ImpersonationConfig{}
+	if len(l) == 0 || l[0] == nil {
+		return ic
+	}
+	in := l[0].(map[string]interface{})
+	ic.UserName = in["user"].(string)
+	ic.Groups = expandStringSlice(in["groups"].([]interface{}))
+	for _, e := range in["extra"].([]interface{}) {
+		extra := e.(map[string]interface{})
+		if ic.Extra == nil {
+			ic.Extra = make(map[string][]string)
+		}
+		ic.Extra[extra["key"].(string)] = expandStringSlice(extra["values"].([]interface{}))
+	}
+	return ic
+}
+
+// wrapImpersonateUID adds the Impersonate-Uid header to every request, as
+// this version of client-go has no setting for it.
+func wrapImpersonateUID(cfg *restclient.Config, l []interface{}) {
+	if len(l) == 0 || l[0] == nil {
+		return
+	}
+	uid := l[0].(map[string]interface{})["uid"].(string)
+	if uid == "" {
+		return
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &impersonateUIDRoundTripper{rt: rt, uid: uid}
+	})
+}
+
+// configUser returns the name of the kubeconfig user of the current context,
+// or the basic auth username when one is set.
+func configUser(cc clientcmd.ClientConfig, overrides *clientcmd.ConfigOverrides) string {
+	if overrides.AuthInfo.Username != "" {
+		return overrides.AuthInfo.Username
+	}
+	if overrides.Context.AuthInfo != "" {
+		return overrides.Context.AuthInfo
+	}
+	raw, err := cc.RawConfig()
+	if err != nil {
+		return ""
+	}
+	current := raw.CurrentContext
+	if overrides.CurrentContext != "" {
+		current = overrides.CurrentContext
 	}
+	if c, ok := raw.Contexts[current]; ok {
+		return c.AuthInfo
+	}
+	return ""
+}
 
-	return cfg, nil
+// inClusterNamespace returns the namespace of the pod the provider runs in,
+// as client-go does for in-cluster clients.
+func inClusterNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
 }
@@ -0,0 +1,402 @@
+package po
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+var alertmanagerKind = &objectKind{
+	kind: po_types.AlertmanagersKind,
+	gvr:  po_types.SchemeGroupVersion.WithResource(po_types.AlertmanagerName),
+	gvk:  po_types.SchemeGroupVersion.WithKind(po_types.AlertmanagersKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types.Alertmanager{ObjectMeta: metadata, Spec: *spec.(*po_types.AlertmanagerSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandAlertmanagerSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenAlertmanagerSpec(obj.(*po_types.Alertmanager).Spec), nil
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1().Alertmanagers(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types.Alertmanager), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+	rollout: &objectRollout{
+		statefulSets: alertmanagerStatefulSets,
+		expected: func(obj runtime.Object) (int, int32) {
+			am := obj.(*po_types.Alertmanager)
+			replicas := int32(1)
+			if am.Spec.Replicas != nil {
+				replicas = *am.Spec.Replicas
+			}
+			return 1, replicas
+		},
+		paused: func(d *schema.ResourceData) bool {
+			return d.Get("spec.0.paused").(bool)
+		},
+	},
+}
+
+func resourcePoAlertmanager() *schema.Resource {
+	return alertmanagerKind.resource(&schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("alertmanager", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Spec defines the specification of the desired behavior of the Alertmanager cluster. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#alertmanagerspec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: alertmanagerSpecSchema(),
+				},
+			},
+			"wait_for_rollout": {
+				Type:        schema.TypeBool,
+				Description: "Wait for the StatefulSet managed by the operator to have all replicas ready on create and update. There is no wait while `spec.paused` is set, as the operator does not reconcile a paused Alertmanager.",
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	})
+}
+
+func alertmanagerSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"replicas": {
+			Type:         schema.TypeInt,
+			Description:  "Size is the expected size of the alertmanager cluster.",
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validateNonNegativeInteger,
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Description: "Version the cluster should be on.",
+			Optional:    true,
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Description: "Image if specified has precedence over baseImage, tag and sha combinations.",
+			Optional:    true,
+		},
+		"retention": {
+			Type:        schema.TypeString,
+			Description: "Time duration Alertmanager shall retain data for. Default is '120h'.",
+			Optional:    true,
+		},
+		"config_secret": {
+			Type:        schema.TypeString,
+			Description: "ConfigSecret is the name of a Kubernetes Secret in the same namespace as the Alertmanager object, which contains configuration for this Alertmanager instance. Defaults to 'alertmanager-<alertmanager-name>'.",
+			Optional:    true,
+		},
+		"secrets": {
+			Type:        schema.TypeList,
+			Description: "Secrets is a list of Secrets in the same namespace as the Alertmanager object, which shall be mounted into the Alertmanager Pods.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"config_maps": {
+			Type:        schema.TypeList,
+			Description: "ConfigMaps is a list of ConfigMaps in the same namespace as the Alertmanager object, which shall be mounted into the Alertmanager Pods.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"alertmanager_config_selector":           optionalLabelSelectorSchema("AlertmanagerConfigs to be selected for to merge and configure Alertmanager with."),
+		"alertmanager_config_namespace_selector": optionalLabelSelectorSchema("Namespaces to be selected for AlertmanagerConfig discovery. If nil, only check own namespace."),
+		"external_url": {
+			Type:        schema.TypeString,
+			Description: "The external URL the Alertmanager instances will be available under.",
+			Optional:    true,
+		},
+		"route_prefix": {
+			Type:        schema.TypeString,
+			Description: "The route prefix Alertmanager registers HTTP handlers for.",
+			Optional:    true,
+		},
+		"log_level": {
+			Type:        schema.TypeString,
+			Description: "Log level for Alertmanager to be configured with.",
+			Optional:    true,
+		},
+		"log_format": {
+			Type:        schema.TypeString,
+			Description: "Log format for Alertmanager to be configured with.",
+			Optional:    true,
+		},
+		"paused": {
+			Type:        schema.TypeBool,
+			Description: "If set to true all actions on the underlying managed objects are not going to be performed, except for delete actions.",
+			Optional:    true,
+		},
+		"listen_local": {
+			Type:        schema.TypeBool,
+			Description: "ListenLocal makes the Alertmanager server listen on loopback, so that it does not bind against the Pod IP.",
+			Optional:    true,
+		},
+		"service_account_name": {
+			Type:        schema.TypeString,
+			Description: "ServiceAccountName is the name of the ServiceAccount to use to run the Alertmanager Pods.",
+			Optional:    true,
+		},
+		"priority_class_name": {
+			Type:        schema.TypeString,
+			Description: "Priority class assigned to the Pods.",
+			Optional:    true,
+		},
+		"storage": {
+			Type:        schema.TypeList,
+			Description: "Storage is the definition of how storage will be used by the Alertmanager instances. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#storagespec",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: storageSpecSchema(),
+			},
+		},
+		"resources": {
+			Type:        schema.TypeList,
+			Description: "Define resources requests and limits for single Pods.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: resourcesField(),
+			},
+		},
+		"node_selector": {
+			Type:        schema.TypeMap,
+			Description: "Define which Nodes the Pods are scheduled on.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"tolerations": {
+			Type:        schema.TypeList,
+			Description: "If specified, the pod's tolerations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: tolerationFields(),
+			},
+		},
+		"affinity": {
+			Type:        schema.TypeList,
+			Description: "If specified, the pod's scheduling constraints.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: affinityFields(),
+			},
+		},
+		"security_context": {
+			Type:        schema.TypeList,
+			Description: "SecurityContext holds pod-level security attributes and common container settings.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: podSecurityContextFields(),
+			},
+		},
+		"additional_peers": {
+			Type:        schema.TypeList,
+			Description: "AdditionalPeers allows injecting a set of additional Alertmanagers to peer with to form a highly available cluster.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"cluster_advertise_address": {
+			Type:        schema.TypeString,
+			Description: "ClusterAdvertiseAddress is the explicit address to advertise in cluster. Needs to be provided for non RFC1918 [1] (public) addresses.",
+			Optional:    true,
+		},
+		"cluster_gossip_interval": {
+			Type:        schema.TypeString,
+			Description: "Interval between gossip attempts.",
+			Optional:    true,
+		},
+		"cluster_pushpull_interval": {
+			Type:        schema.TypeString,
+			Description: "Interval between pushpull attempts.",
+			Optional:    true,
+		},
+		"cluster_peer_timeout": {
+			Type:        schema.TypeString,
+			Description: "Timeout for cluster peering.",
+			Optional:    true,
+		},
+		"force_enable_cluster_mode": {
+			Type:        schema.TypeBool,
+			Description: "ForceEnableClusterMode ensures Alertmanager does not deactivate the cluster mode when running with a single replica.",
+			Optional:    true,
+		},
+	}
+}
+
+// alertmanagerStatefulSets selects the StatefulSet the operator creates for
+// the named Alertmanager.
+func alertmanagerStatefulSets(name string) metav1.ListOptions {
+	return metav1.ListOptions{
+		FieldSelector: "metadata.name=alertmanager-" + name,
+	}
+}
+
+func expandAlertmanagerSpec(p []interface{}) (*po_types.AlertmanagerSpec, error) {
+	obj := &po_types.AlertmanagerSpec{}
+	if len(p) == 0 || p[0] == nil {
+		return obj, nil
+	}
+	in := p[0].(map[string]interface{})
+
+	obj.Replicas = ptrToInt32(int32(in["replicas"].(int)))
+	obj.Version = in["version"].(string)
+	if v, ok := in["image"].(string); ok && v != "" {
+		obj.Image = ptrToString(v)
+	}
+	obj.Retention = in["retention"].(string)
+	obj.ConfigSecret = in["config_secret"].(string)
+	if v, ok := in["secrets"].([]interface{}); ok && len(v) > 0 {
+		obj.Secrets = expandStringSlice(v)
+	}
+	if v, ok := in["config_maps"].([]interface{}); ok && len(v) > 0 {
+		obj.ConfigMaps = expandStringSlice(v)
+	}
+	if v, ok := in["alertmanager_config_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.AlertmanagerConfigSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["alertmanager_config_namespace_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.AlertmanagerConfigNamespaceSelector = expandLabelSelector(v)
+	}
+	obj.ExternalURL = in["external_url"].(string)
+	obj.RoutePrefix = in["route_prefix"].(string)
+	obj.LogLevel = in["log_level"].(string)
+	obj.LogFormat = in["log_format"].(string)
+	obj.Paused = in["paused"].(bool)
+	obj.ListenLocal = in["listen_local"].(bool)
+	obj.ServiceAccountName = in["service_account_name"].(string)
+	obj.PriorityClassName = in["priority_class_name"].(string)
+
+	if v, ok := in["storage"].([]interface{}); ok && len(v) > 0 {
+		storage, err := expandStorageSpec(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Storage = storage
+	}
+	if v, ok := in["resources"].([]interface{}); ok && len(v) > 0 {
+		resources, err := expandContainerResourceRequirements(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Resources = *resources
+	}
+	if v, ok := in["node_selector"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.NodeSelector = expandStringMap(v)
+	}
+	if v, ok := in["tolerations"].([]interface{}); ok && len(v) > 0 {
+		tolerations, err := expandTolerations(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Tolerations = tolerations
+	}
+	if v, ok := in["affinity"].([]interface{}); ok && len(v) > 0 {
+		obj.Affinity = expandAffinity(v)
+	}
+	if v, ok := in["security_context"].([]interface{}); ok && len(v) > 0 {
+		sc, err := expandPodSecurityContext(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.SecurityContext = sc
+	}
+
+	if v, ok := in["additional_peers"].([]interface{}); ok && len(v) > 0 {
+		obj.AdditionalPeers = expandStringSlice(v)
+	}
+	obj.ClusterAdvertiseAddress = in["cluster_advertise_address"].(string)
+	obj.ClusterGossipInterval = in["cluster_gossip_interval"].(string)
+	obj.ClusterPushpullInterval = in["cluster_pushpull_interval"].(string)
+	obj.ClusterPeerTimeout = in["cluster_peer_timeout"].(string)
+	obj.ForceEnableClusterMode = in["force_enable_cluster_mode"].(bool)
+	return obj, nil
+}
+
+func flattenAlertmanagerSpec(spec po_types.AlertmanagerSpec) []interface{} {
+	att := make(map[string]interface{})
+
+	att["replicas"] = 1
+	if spec.Replicas != nil {
+		att["replicas"] = int(*spec.Replicas)
+	}
+	att["version"] = spec.Version
+	if spec.Image != nil {
+		att["image"] = *spec.Image
+	}
+	att["retention"] = spec.Retention
+	att["config_secret"] = spec.ConfigSecret
+	att["secrets"] = spec.Secrets
+	att["config_maps"] = spec.ConfigMaps
+	if spec.AlertmanagerConfigSelector != nil {
+		att["alertmanager_config_selector"] = flattenLabelSelector(spec.AlertmanagerConfigSelector)
+	}
+	if spec.AlertmanagerConfigNamespaceSelector != nil {
+		att["alertmanager_config_namespace_selector"] = flattenLabelSelector(spec.AlertmanagerConfigNamespaceSelector)
+	}
+	att["external_url"] = spec.ExternalURL
+	att["route_prefix"] = spec.RoutePrefix
+	att["log_level"] = spec.LogLevel
+	att["log_format"] = spec.LogFormat
+	att["paused"] = spec.Paused
+	att["listen_local"] = spec.ListenLocal
+	att["service_account_name"] = spec.ServiceAccountName
+	att["priority_class_name"] = spec.PriorityClassName
+
+	if spec.Storage != nil {
+		att["storage"] = flattenStorageSpec(spec.Storage)
+	}
+	if len(spec.Resources.Limits) > 0 || len(spec.Resources.Requests) > 0 {
+		att["resources"] = flattenContainerResourceRequirements(spec.Resources)
+	}
+	att["node_selector"] = spec.NodeSelector
+	att["tolerations"] = flattenTolerations(spec.Tolerations)
+	if spec.Affinity != nil {
+		att["affinity"] = flattenAffinity(spec.Affinity)
+	}
+	if spec.SecurityContext != nil {
+		att["security_context"] = flattenPodSecurityContext(spec.SecurityContext)
+	}
+
+	att["additional_peers"] = spec.AdditionalPeers
+	att["cluster_advertise_address"] = spec.ClusterAdvertiseAddress
+	att["cluster_gossip_interval"] = spec.ClusterGossipInterval
+	att["cluster_pushpull_interval"] = spec.ClusterPushpullInterval
+	att["cluster_peer_timeout"] = spec.ClusterPeerTimeout
+	att["force_enable_cluster_mode"] = spec.ForceEnableClusterMode
+
+	return []interface{}{att}
+}
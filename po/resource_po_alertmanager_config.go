@@ -0,0 +1,355 @@
+package po
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types_alpha "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+// maxRouteDepth bounds how many levels of child routes can be declared, since
+// a Terraform schema can't be recursive.
+const maxRouteDepth = 4
+
+var alertmanagerConfigKind = &objectKind{
+	kind: po_types_alpha.AlertmanagerConfigKind,
+	gvr:  po_types_alpha.SchemeGroupVersion.WithResource(po_types_alpha.AlertmanagerConfigName),
+	gvk:  po_types_alpha.SchemeGroupVersion.WithKind(po_types_alpha.AlertmanagerConfigKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types_alpha.AlertmanagerConfig{ObjectMeta: metadata, Spec: *spec.(*po_types_alpha.AlertmanagerConfigSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandAlertmanagerConfigSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenAlertmanagerConfigSpec(obj.(*po_types_alpha.AlertmanagerConfig).Spec)
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1alpha1().AlertmanagerConfigs(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types_alpha.AlertmanagerConfig), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+}
+
+func resourcePoAlertmanagerConfig() *schema.Resource {
+	return alertmanagerConfigKind.resource(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("alertmanager config", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "AlertmanagerConfigSpec is a specification of the desired behavior of the Alertmanager configuration. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#alertmanagerconfigspec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"route": {
+							Type:        schema.TypeList,
+							Description: "The Alertmanager route definition for alerts matching the resource's namespace. It will be added to the generated Alertmanager configuration as a first-level route.",
+							Optional:    true,
+							MaxItems:    1,
+							Elem: &schema.Resource{
+								Schema: routeSchema(maxRouteDepth),
+							},
+						},
+						"receivers": {
+							Type:        schema.TypeList,
+							Description: "List of receivers.",
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: receiverSchema(),
+							},
+						},
+						"inhibit_rules": {
+							Type:        schema.TypeList,
+							Description: "List of inhibition rules. The rules will only apply to alerts matching the resource's namespace.",
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: inhibitRuleSchema(),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func routeSchema(depth int) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"receiver": {
+			Type:        schema.TypeString,
+			Description: "Name of the receiver for this route. If not empty, it should be listed in the `receivers` field.",
+			Optional:    true,
+		},
+		"group_by": {
+			Type:        schema.TypeList,
+			Description: "List of labels to group by.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"group_wait": {
+			Type:        schema.TypeString,
+			Description: "How long to wait before sending the initial notification. Must match the regular expression `[0-9]+(ms|s|m|h)`.",
+			Optional:    true,
+		},
+		"group_interval": {
+			Type:        schema.TypeString,
+			Description: "How long to wait before sending an updated notification. Must match the regular expression `[0-9]+(ms|s|m|h)`.",
+			Optional:    true,
+		},
+		"repeat_interval": {
+			Type:        schema.TypeString,
+			Description: "How long to wait before repeating the last notification. Must match the regular expression `[0-9]+(ms|s|m|h)`.",
+			Optional:    true,
+		},
+		"matchers": {
+			Type:        schema.TypeList,
+			Description: "List of matchers that the alert's labels should match.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: matcherSchema(),
+			},
+		},
+		"continue": {
+			Type:        schema.TypeBool,
+			Description: "Boolean indicating whether an alert should continue matching subsequent sibling nodes.",
+			Optional:    true,
+		},
+	}
+	if depth > 1 {
+		s["routes"] = &schema.Schema{
+			Type:        schema.TypeList,
+			Description: "Child routes.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: routeSchema(depth - 1),
+			},
+		}
+	}
+	return s
+}
+
+func matcherSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Label to match.",
+			Required:    true,
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Description: "Label value to match.",
+			Optional:    true,
+		},
+		"regex": {
+			Type:        schema.TypeBool,
+			Description: "Whether to match on equality (false) or regular-expression (true).",
+			Optional:    true,
+		},
+	}
+}
+
+func inhibitRuleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"target_match": {
+			Type:        schema.TypeList,
+			Description: "Matchers that have to be fulfilled in the alerts to be muted.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: matcherSchema(),
+			},
+		},
+		"source_match": {
+			Type:        schema.TypeList,
+			Description: "Matchers for which one or more alerts have to exist for the inhibition to take effect.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: matcherSchema(),
+			},
+		},
+		"equal": {
+			Type:        schema.TypeList,
+			Description: "Labels that must have an equal value in the source and target alert for the inhibition to take effect.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func expandAlertmanagerConfigSpec(p []interface{}) (*po_types_alpha.AlertmanagerConfigSpec, error) {
+	obj := &po_types_alpha.AlertmanagerConfigSpec{}
+	if len(p) == 0 || p[0] == nil {
+		return obj, nil
+	}
+	in := p[0].(map[string]interface{})
+
+	if v, ok := in["route"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		route, err := expandRoute(v[0].(map[string]interface{}))
+		if err != nil {
+			return obj, err
+		}
+		obj.Route = route
+	}
+	receivers, err := expandReceivers(in["receivers"].([]interface{}))
+	if err != nil {
+		return obj, err
+	}
+	obj.Receivers = receivers
+	obj.InhibitRules = expandInhibitRules(in["inhibit_rules"].([]interface{}))
+	return obj, nil
+}
+
+func flattenAlertmanagerConfigSpec(spec po_types_alpha.AlertmanagerConfigSpec) ([]interface{}, error) {
+	att := make(map[string]interface{})
+
+	if spec.Route != nil {
+		route, err := flattenRoute(spec.Route, maxRouteDepth)
+		if err != nil {
+			return nil, err
+		}
+		att["route"] = []interface{}{route}
+	}
+	att["receivers"] = flattenReceivers(spec.Receivers)
+	att["inhibit_rules"] = flattenInhibitRules(spec.InhibitRules)
+
+	return []interface{}{att}, nil
+}
+
+func expandRoute(in map[string]interface{}) (*po_types_alpha.Route, error) {
+	obj := &po_types_alpha.Route{}
+	obj.Receiver = in["receiver"].(string)
+	if v, ok := in["group_by"].([]interface{}); ok && len(v) > 0 {
+		obj.GroupBy = expandStringSlice(v)
+	}
+	obj.GroupWait = in["group_wait"].(string)
+	obj.GroupInterval = in["group_interval"].(string)
+	obj.RepeatInterval = in["repeat_interval"].(string)
+	obj.Matchers = expandMatchers(in["matchers"].([]interface{}))
+	obj.Continue = in["continue"].(bool)
+
+	// Child routes are stored as raw JSON in the CRD.
+	if v, ok := in["routes"].([]interface{}); ok {
+		for i, r := range v {
+			child, err := expandRoute(r.(map[string]interface{}))
+			if err != nil {
+				return obj, err
+			}
+			raw, err := json.Marshal(child)
+			if err != nil {
+				return obj, fmt.Errorf("route[%d]: %w", i, err)
+			}
+			obj.Routes = append(obj.Routes, apiextensionsv1.JSON{Raw: raw})
+		}
+	}
+	return obj, nil
+}
+
+// Child routes nested deeper than depth are dropped since the schema has no
+// place to store them.
+func flattenRoute(in *po_types_alpha.Route, depth int) (map[string]interface{}, error) {
+	att := make(map[string]interface{})
+	att["receiver"] = in.Receiver
+	att["group_by"] = in.GroupBy
+	att["group_wait"] = in.GroupWait
+	att["group_interval"] = in.GroupInterval
+	att["repeat_interval"] = in.RepeatInterval
+	att["matchers"] = flattenMatchers(in.Matchers)
+	att["continue"] = in.Continue
+
+	children, err := in.ChildRoutes()
+	if err != nil {
+		return nil, err
+	}
+	if len(children) > 0 && depth > 1 {
+		routes := make([]interface{}, len(children))
+		for i := range children {
+			r, err := flattenRoute(&children[i], depth-1)
+			if err != nil {
+				return nil, err
+			}
+			routes[i] = r
+		}
+		att["routes"] = routes
+	}
+	return att, nil
+}
+
+func expandMatchers(l []interface{}) []po_types_alpha.Matcher {
+	if len(l) == 0 {
+		return nil
+	}
+	obj := make([]po_types_alpha.Matcher, len(l))
+	for i, m := range l {
+		in := m.(map[string]interface{})
+		obj[i] = po_types_alpha.Matcher{
+			Name:  in["name"].(string),
+			Value: in["value"].(string),
+			Regex: in["regex"].(bool),
+		}
+	}
+	return obj
+}
+
+func flattenMatchers(in []po_types_alpha.Matcher) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, m := range in {
+		att[i] = map[string]interface{}{
+			"name":  m.Name,
+			"value": m.Value,
+			"regex": m.Regex,
+		}
+	}
+	return att
+}
+
+func expandInhibitRules(l []interface{}) []po_types_alpha.InhibitRule {
+	if len(l) == 0 {
+		return nil
+	}
+	obj := make([]po_types_alpha.InhibitRule, len(l))
+	for i, r := range l {
+		in := r.(map[string]interface{})
+		obj[i].TargetMatch = expandMatchers(in["target_match"].([]interface{}))
+		obj[i].SourceMatch = expandMatchers(in["source_match"].([]interface{}))
+		if v, ok := in["equal"].([]interface{}); ok && len(v) > 0 {
+			obj[i].Equal = expandStringSlice(v)
+		}
+	}
+	return obj
+}
+
+func flattenInhibitRules(in []po_types_alpha.InhibitRule) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, r := range in {
+		att[i] = map[string]interface{}{
+			"target_match": flattenMatchers(r.TargetMatch),
+			"source_match": flattenMatchers(r.SourceMatch),
+			"equal":        r.Equal,
+		}
+	}
+	return att
+}
@@ -0,0 +1,175 @@
+package po
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+var podMonitorKind = &objectKind{
+	kind: po_types.PodMonitorsKind,
+	gvr:  po_types.SchemeGroupVersion.WithResource(po_types.PodMonitorName),
+	gvk:  po_types.SchemeGroupVersion.WithKind(po_types.PodMonitorsKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types.PodMonitor{ObjectMeta: metadata, Spec: *spec.(*po_types.PodMonitorSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandPodMonitorSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenPodMonitorSpec(obj.(*po_types.PodMonitor).Spec)
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1().PodMonitors(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types.PodMonitor), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+}
+
+func resourcePoPodMonitor() *schema.Resource {
+	return podMonitorKind.resource(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("pod monitor", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Spec defines the specification of the desired behavior of the pod monitor. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmonitorspec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: podMonitorSpecSchema(),
+				},
+			},
+		},
+	})
+}
+
+func podMonitorSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"job_label": {
+			Type:        schema.TypeString,
+			Description: "The label to use to retrieve the job name from. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmonitorspec",
+			Optional:    true,
+		},
+		"pod_target_labels": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "PodTargetLabels transfers labels on the Kubernetes Pod onto the target. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmonitorspec",
+		},
+		"pod_metrics_endpoints": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "A list of endpoints allowed as part of this PodMonitor. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmonitorspec",
+			Elem: &schema.Resource{
+				Schema: podMetricsEndpointSchema(),
+			},
+		},
+		"selector": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Selector to select Pod objects.",
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: labelSelectorFields(true),
+			},
+		},
+		"namespace_selector": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Selector to select which namespaces the Pod objects are discovered from.",
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: namespaceSelectorSchema(),
+			},
+		},
+		"sample_limit": {
+			Type:        schema.TypeInt,
+			Description: "SampleLimit defines per-scrape limit on number of scraped samples that will be accepted. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmonitorspec",
+			Optional:    true,
+		},
+		"target_limit": {
+			Type:        schema.TypeInt,
+			Description: "TargetLimit defines a limit on the number of scraped targets that will be accepted. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmonitorspec",
+			Optional:    true,
+		},
+	}
+}
+
+func expandPodMonitorSpec(pm []interface{}) (*po_types.PodMonitorSpec, error) {
+	obj := &po_types.PodMonitorSpec{}
+	if len(pm) == 0 || pm[0] == nil {
+		return obj, nil
+	}
+	in := pm[0].(map[string]interface{})
+
+	obj.JobLabel = in["job_label"].(string)
+	obj.SampleLimit = uint64(in["sample_limit"].(int))
+	obj.TargetLimit = uint64(in["target_limit"].(int))
+	if ptl, ok := in["pod_target_labels"].([]interface{}); ok {
+		obj.PodTargetLabels = expandStringSlice(ptl)
+	}
+	if v, ok := in["pod_metrics_endpoints"].([]interface{}); ok && len(v) > 0 {
+		endpoints, err := expandPodMetricsEndpoints(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.PodMetricsEndpoints = endpoints
+	}
+	if s, ok := in["selector"].([]interface{}); ok && len(s) > 0 {
+		selector := expandLabelSelector(s)
+		obj.Selector = *selector
+	}
+	if ns, ok := in["namespace_selector"].([]interface{}); ok && len(ns) > 0 {
+		selector, err := expandNamespaceSelector(ns)
+		if err != nil {
+			return obj, err
+		}
+		obj.NamespaceSelector = *selector
+	}
+	return obj, nil
+}
+
+func flattenPodMonitorSpec(spec po_types.PodMonitorSpec) ([]interface{}, error) {
+	att := make(map[string]interface{})
+
+	if spec.JobLabel != "" {
+		att["job_label"] = spec.JobLabel
+	}
+	if len(spec.PodTargetLabels) > 0 {
+		att["pod_target_labels"] = spec.PodTargetLabels
+	}
+	att["sample_limit"] = int(spec.SampleLimit)
+	att["target_limit"] = int(spec.TargetLimit)
+
+	endpoints, err := flattenPodMetricsEndpoints(spec.PodMetricsEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	att["pod_metrics_endpoints"] = endpoints
+	att["selector"] = flattenOptionalLabelSelector(&spec.Selector)
+	att["namespace_selector"] = flattenOptionalNamespaceSelector(&spec.NamespaceSelector)
+
+	return []interface{}{att}, nil
+}
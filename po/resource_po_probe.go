@@ -0,0 +1,394 @@
+package po
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+var probeKind = &objectKind{
+	kind: po_types.ProbesKind,
+	gvr:  po_types.SchemeGroupVersion.WithResource(po_types.ProbeName),
+	gvk:  po_types.SchemeGroupVersion.WithKind(po_types.ProbesKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types.Probe{ObjectMeta: metadata, Spec: *spec.(*po_types.ProbeSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandProbeSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenProbeSpec(obj.(*po_types.Probe).Spec)
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1().Probes(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types.Probe), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+}
+
+func resourcePoProbe() *schema.Resource {
+	return probeKind.resource(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("probe", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Spec defines the specification of desired Ingress selection for target discovery by Prometheus. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#probespec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: probeSpecSchema(),
+				},
+			},
+		},
+	})
+}
+
+func probeSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"job_name": {
+			Type:        schema.TypeString,
+			Description: "The job name assigned to scraped metrics by default.",
+			Optional:    true,
+		},
+		"prober": {
+			Type:        schema.TypeList,
+			Description: "Specification for the prober to use for probing targets. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#proberspec",
+			Required:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: proberSpecSchema(),
+			},
+		},
+		"module": {
+			Type:        schema.TypeString,
+			Description: "The module to use for probing specifying how to probe the target. More info: https://github.com/prometheus/blackbox_exporter/blob/master/example.yml",
+			Optional:    true,
+		},
+		"targets": {
+			Type:        schema.TypeList,
+			Description: "Targets defines a set of static and/or dynamically discovered targets to be probed using the prober. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#probetargets",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: probeTargetsSchema(),
+			},
+		},
+		"interval": {
+			Type:        schema.TypeString,
+			Description: "Interval at which targets are probed using the configured prober.",
+			Optional:    true,
+		},
+		"scrape_timeout": {
+			Type:        schema.TypeString,
+			Description: "Timeout for scraping metrics from the Prometheus exporter.",
+			Optional:    true,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "TLS configuration to use when scraping the endpoint. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#probetlsconfig",
+			Elem: &schema.Resource{
+				Schema: safeTLSConfigSchema(),
+			},
+		},
+		"bearer_token_secret": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Secret to mount to read bearer token for scraping targets. The secret needs to be in the same namespace as the probe and accessible by the Prometheus Operator.",
+			Elem: &schema.Resource{
+				Schema: secretKeySelectorSchema(),
+			},
+		},
+		"basic_auth": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "BasicAuth allow an endpoint to authenticate over basic authentication More info: https://prometheus.io/docs/operating/configuration/#endpoints",
+			Elem: &schema.Resource{
+				Schema: basicAuthSchema(),
+			},
+		},
+	}
+}
+
+func proberSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"url": {
+			Type:        schema.TypeString,
+			Description: "Mandatory URL of the prober.",
+			Required:    true,
+		},
+		"scheme": {
+			Type:        schema.TypeString,
+			Description: "HTTP scheme to use for scraping. Defaults to `http`.",
+			Optional:    true,
+		},
+		"path": {
+			Type:        schema.TypeString,
+			Description: "Path to collect metrics from. Defaults to `/probe`.",
+			Optional:    true,
+		},
+		"proxy_url": {
+			Type:        schema.TypeString,
+			Description: "ProxyURL eg http://proxyserver:2195 Directs scrapes to proxy through this endpoint.",
+			Optional:    true,
+		},
+	}
+}
+
+func probeTargetsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"static_config": {
+			Type:        schema.TypeList,
+			Description: "StaticConfig defines static targets which are considers for probing. More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#static_config",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"static": {
+						Type:        schema.TypeList,
+						Description: "Targets is a list of URLs to probe using the configured prober.",
+						Optional:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"labels": {
+						Type:        schema.TypeMap,
+						Description: "Labels assigned to all metrics scraped from the targets.",
+						Optional:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+					},
+					"relabelings": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "RelabelConfigs to apply to samples before ingestion. More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config",
+						Elem: &schema.Resource{
+							Schema: relabelConfigSchema(),
+						},
+					},
+				},
+			},
+		},
+		"ingress": {
+			Type:        schema.TypeList,
+			Description: "Ingress defines the set of dynamically discovered ingress objects which hosts are considered for probing.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"selector": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Select Ingress objects by labels.",
+						MaxItems:    1,
+						Elem: &schema.Resource{
+							Schema: labelSelectorFields(true),
+						},
+					},
+					"namespace_selector": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Select Ingress objects by namespace.",
+						MaxItems:    1,
+						Elem: &schema.Resource{
+							Schema: namespaceSelectorSchema(),
+						},
+					},
+					"relabelings": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "RelabelConfigs to apply to samples before ingestion. More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config",
+						Elem: &schema.Resource{
+							Schema: relabelConfigSchema(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func expandProbeSpec(p []interface{}) (*po_types.ProbeSpec, error) {
+	obj := &po_types.ProbeSpec{}
+	if len(p) == 0 || p[0] == nil {
+		return obj, nil
+	}
+	in := p[0].(map[string]interface{})
+
+	obj.JobName = in["job_name"].(string)
+	obj.Module = in["module"].(string)
+	obj.Interval = in["interval"].(string)
+	obj.ScrapeTimeout = in["scrape_timeout"].(string)
+	if v, ok := in["prober"].([]interface{}); ok && len(v) > 0 {
+		obj.ProberSpec = *expandProberSpec(v)
+	}
+	if v, ok := in["targets"].([]interface{}); ok && len(v) > 0 {
+		targets, err := expandProbeTargets(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Targets = *targets
+	}
+	if tls, ok := in["tls_config"].([]interface{}); ok && len(tls) > 0 {
+		tls, err := expandSafeTLSConfig(tls)
+		if err != nil {
+			return obj, err
+		}
+		obj.TLSConfig = &po_types.ProbeTLSConfig{SafeTLSConfig: *tls}
+	}
+	if bts, ok := in["bearer_token_secret"].([]interface{}); ok && len(bts) > 0 {
+		s, err := expandSecretKeyRef(bts)
+		if err != nil {
+			return obj, err
+		}
+		obj.BearerTokenSecret = *s
+	}
+	if ba, ok := in["basic_auth"].([]interface{}); ok && len(ba) > 0 {
+		ba, err := expandBasicAuth(ba)
+		if err != nil {
+			return obj, err
+		}
+		obj.BasicAuth = ba
+	}
+	return obj, nil
+}
+
+func flattenProbeSpec(spec po_types.ProbeSpec) ([]interface{}, error) {
+	att := make(map[string]interface{})
+
+	if spec.JobName != "" {
+		att["job_name"] = spec.JobName
+	}
+	att["prober"] = flattenProberSpec(spec.ProberSpec)
+	if spec.Module != "" {
+		att["module"] = spec.Module
+	}
+	if spec.Targets.StaticConfig != nil || spec.Targets.Ingress != nil {
+		att["targets"] = flattenProbeTargets(spec.Targets)
+	}
+	att["interval"] = spec.Interval
+	att["scrape_timeout"] = spec.ScrapeTimeout
+	if spec.TLSConfig != nil {
+		att["tls_config"] = flattenSafeTLSConfig(&spec.TLSConfig.SafeTLSConfig)
+	}
+	if spec.BearerTokenSecret.Key != "" || spec.BearerTokenSecret.Name != "" {
+		att["bearer_token_secret"] = flattenSecretKeyRef(&spec.BearerTokenSecret)
+	}
+	if spec.BasicAuth != nil {
+		att["basic_auth"] = flattenBasicAuth(spec.BasicAuth)
+	}
+
+	return []interface{}{att}, nil
+}
+
+func expandProberSpec(l []interface{}) *po_types.ProberSpec {
+	obj := &po_types.ProberSpec{}
+	if len(l) == 0 || l[0] == nil {
+		return obj
+	}
+	in := l[0].(map[string]interface{})
+	obj.URL = in["url"].(string)
+	obj.Scheme = in["scheme"].(string)
+	obj.Path = in["path"].(string)
+	obj.ProxyURL = in["proxy_url"].(string)
+	return obj
+}
+
+func flattenProberSpec(in po_types.ProberSpec) []interface{} {
+	att := make(map[string]interface{})
+	att["url"] = in.URL
+	att["scheme"] = in.Scheme
+	att["path"] = in.Path
+	att["proxy_url"] = in.ProxyURL
+	return []interface{}{att}
+}
+
+func expandProbeTargets(l []interface{}) (*po_types.ProbeTargets, error) {
+	obj := &po_types.ProbeTargets{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
+
+	if v, ok := in["static_config"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		sc := v[0].(map[string]interface{})
+		obj.StaticConfig = &po_types.ProbeTargetStaticConfig{}
+		if t, ok := sc["static"].([]interface{}); ok && len(t) > 0 {
+			obj.StaticConfig.Targets = expandStringSlice(t)
+		}
+		if lbl, ok := sc["labels"].(map[string]interface{}); ok && len(lbl) > 0 {
+			obj.StaticConfig.Labels = expandStringMap(lbl)
+		}
+		if re, ok := sc["relabelings"].([]interface{}); ok && len(re) > 0 {
+			c, err := expandRelabelConfig(re)
+			if err != nil {
+				return obj, err
+			}
+			obj.StaticConfig.RelabelConfigs = c
+		}
+	}
+	if v, ok := in["ingress"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		ing := v[0].(map[string]interface{})
+		obj.Ingress = &po_types.ProbeTargetIngress{}
+		if s, ok := ing["selector"].([]interface{}); ok && len(s) > 0 {
+			obj.Ingress.Selector = *expandLabelSelector(s)
+		}
+		if ns, ok := ing["namespace_selector"].([]interface{}); ok && len(ns) > 0 {
+			selector, err := expandNamespaceSelector(ns)
+			if err != nil {
+				return obj, err
+			}
+			obj.Ingress.NamespaceSelector = *selector
+		}
+		if re, ok := ing["relabelings"].([]interface{}); ok && len(re) > 0 {
+			c, err := expandRelabelConfig(re)
+			if err != nil {
+				return obj, err
+			}
+			obj.Ingress.RelabelConfigs = c
+		}
+	}
+	return obj, nil
+}
+
+func flattenProbeTargets(in po_types.ProbeTargets) []interface{} {
+	att := make(map[string]interface{})
+	if in.StaticConfig != nil {
+		sc := make(map[string]interface{})
+		sc["static"] = in.StaticConfig.Targets
+		sc["labels"] = in.StaticConfig.Labels
+		sc["relabelings"] = flattenRelabelConfig(in.StaticConfig.RelabelConfigs)
+		att["static_config"] = []interface{}{sc}
+	}
+	if in.Ingress != nil {
+		ing := make(map[string]interface{})
+		ing["selector"] = flattenOptionalLabelSelector(&in.Ingress.Selector)
+		ing["namespace_selector"] = flattenOptionalNamespaceSelector(&in.Ingress.NamespaceSelector)
+		ing["relabelings"] = flattenRelabelConfig(in.Ingress.RelabelConfigs)
+		att["ingress"] = []interface{}{ing}
+	}
+	return []interface{}{att}
+}
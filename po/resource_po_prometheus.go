@@ -0,0 +1,567 @@
+package po
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+var prometheusKind = &objectKind{
+	kind: po_types.PrometheusesKind,
+	gvr:  po_types.SchemeGroupVersion.WithResource(po_types.PrometheusName),
+	gvk:  po_types.SchemeGroupVersion.WithKind(po_types.PrometheusesKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types.Prometheus{ObjectMeta: metadata, Spec: *spec.(*po_types.PrometheusSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandPrometheusSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenPrometheusSpec(obj.(*po_types.Prometheus).Spec)
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1().Prometheuses(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types.Prometheus), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+	rollout: &objectRollout{
+		statefulSets: prometheusStatefulSets,
+		expected: func(obj runtime.Object) (int, int32) {
+			p := obj.(*po_types.Prometheus)
+			shards := 1
+			if p.Spec.Shards != nil {
+				shards = int(*p.Spec.Shards)
+			}
+			replicas := int32(1)
+			if p.Spec.Replicas != nil {
+				replicas = *p.Spec.Replicas
+			}
+			return shards, replicas
+		},
+	},
+}
+
+func resourcePoPrometheus() *schema.Resource {
+	return prometheusKind.resource(&schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("prometheus", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Spec defines the specification of the desired behavior of the Prometheus cluster. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#prometheusspec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: prometheusSpecSchema(),
+				},
+			},
+			"wait_for_rollout": {
+				Type:        schema.TypeBool,
+				Description: "Wait for the StatefulSets managed by the operator to have all replicas ready on create and update.",
+				Optional:    true,
+				Default:     true,
+			},
+		},
+	})
+}
+
+func prometheusSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"replicas": {
+			Type:         schema.TypeInt,
+			Description:  "Number of replicas of each shard to deploy for a Prometheus deployment.",
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validateNonNegativeInteger,
+		},
+		"shards": {
+			Type:         schema.TypeInt,
+			Description:  "Number of shards to distribute targets onto. Number of replicas multiplied by shards is the total number of Pods created.",
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validatePositiveInteger,
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Description: "Version of Prometheus to be deployed.",
+			Optional:    true,
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Description: "Image if specified has precedence over baseImage, tag and sha combinations.",
+			Optional:    true,
+		},
+		"retention": {
+			Type:        schema.TypeString,
+			Description: "Time duration Prometheus shall retain data for. Default is '24h'.",
+			Optional:    true,
+		},
+		"retention_size": {
+			Type:        schema.TypeString,
+			Description: "Maximum amount of disk space used by blocks.",
+			Optional:    true,
+		},
+		"scrape_interval": {
+			Type:        schema.TypeString,
+			Description: "Interval between consecutive scrapes. Default: `1m`",
+			Optional:    true,
+		},
+		"evaluation_interval": {
+			Type:        schema.TypeString,
+			Description: "Interval between consecutive evaluations. Default: `1m`",
+			Optional:    true,
+		},
+		"external_url": {
+			Type:        schema.TypeString,
+			Description: "The external URL the Prometheus instances will be available under.",
+			Optional:    true,
+		},
+		"route_prefix": {
+			Type:        schema.TypeString,
+			Description: "The route prefix Prometheus registers HTTP handlers for.",
+			Optional:    true,
+		},
+		"log_level": {
+			Type:        schema.TypeString,
+			Description: "Log level for Prometheus to be configured with.",
+			Optional:    true,
+		},
+		"service_account_name": {
+			Type:        schema.TypeString,
+			Description: "ServiceAccountName is the name of the ServiceAccount to use to run the Prometheus Pods.",
+			Optional:    true,
+		},
+		"priority_class_name": {
+			Type:        schema.TypeString,
+			Description: "Priority class assigned to the Pods.",
+			Optional:    true,
+		},
+		"service_monitor_selector":           optionalLabelSelectorSchema("ServiceMonitors to be selected for target discovery. An empty selector matches all objects, no selector matches none."),
+		"service_monitor_namespace_selector": optionalLabelSelectorSchema("Namespaces to be selected for ServiceMonitor discovery. If not set, only the Prometheus object's own namespace is checked."),
+		"pod_monitor_selector":               optionalLabelSelectorSchema("PodMonitors to be selected for target discovery. An empty selector matches all objects, no selector matches none."),
+		"pod_monitor_namespace_selector":     optionalLabelSelectorSchema("Namespaces to be selected for PodMonitor discovery. If not set, only the Prometheus object's own namespace is checked."),
+		"probe_selector":                     optionalLabelSelectorSchema("Probes to be selected for target discovery. An empty selector matches all objects, no selector matches none."),
+		"probe_namespace_selector":           optionalLabelSelectorSchema("Namespaces to be selected for Probe discovery. If not set, only the Prometheus object's own namespace is checked."),
+		"rule_selector":                      optionalLabelSelectorSchema("A selector to select which PrometheusRules to mount for loading alerting/recording rules from."),
+		"rule_namespace_selector":            optionalLabelSelectorSchema("Namespaces to be selected for PrometheusRules discovery. If not set, only the Prometheus object's own namespace is checked."),
+		"alerting": {
+			Type:        schema.TypeList,
+			Description: "Define details regarding alerting. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#alertingspec",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: alertingSpecSchema(),
+			},
+		},
+		"remote_write": {
+			Type:        schema.TypeList,
+			Description: "If specified, the remote_write spec. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#remotewritespec",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: remoteWriteSpecSchema(),
+			},
+		},
+		"remote_read": {
+			Type:        schema.TypeList,
+			Description: "If specified, the remote_read spec. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#remotereadspec",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: remoteReadSpecSchema(),
+			},
+		},
+		"storage": {
+			Type:        schema.TypeList,
+			Description: "Storage spec to specify how storage shall be used. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#storagespec",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: storageSpecSchema(),
+			},
+		},
+		"resources": {
+			Type:        schema.TypeList,
+			Description: "Define resources requests and limits for single Pods.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: resourcesField(),
+			},
+		},
+		"node_selector": {
+			Type:        schema.TypeMap,
+			Description: "Define which Nodes the Pods are scheduled on.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"tolerations": {
+			Type:        schema.TypeList,
+			Description: "If specified, the pod's tolerations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: tolerationFields(),
+			},
+		},
+		"affinity": {
+			Type:        schema.TypeList,
+			Description: "If specified, the pod's scheduling constraints.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: affinityFields(),
+			},
+		},
+		"security_context": {
+			Type:        schema.TypeList,
+			Description: "SecurityContext holds pod-level security attributes and common container settings.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: podSecurityContextFields(),
+			},
+		},
+		"external_labels": {
+			Type:        schema.TypeMap,
+			Description: "The labels to add to any time series or alerts when communicating with external systems (federation, remote storage, Alertmanager).",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"thanos": {
+			Type:        schema.TypeList,
+			Description: "Thanos configuration allows configuring various aspects of a Prometheus server in a Thanos environment. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#thanosspec",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: thanosSpecSchema(),
+			},
+		},
+	}
+}
+
+func thanosSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"image": {
+			Type:        schema.TypeString,
+			Description: "Image if specified has precedence over baseImage, tag and sha combinations.",
+			Optional:    true,
+		},
+		"version": {
+			Type:        schema.TypeString,
+			Description: "Version describes the version of Thanos to use.",
+			Optional:    true,
+		},
+		"resources": {
+			Type:        schema.TypeList,
+			Description: "Resources defines the resource requirements for the Thanos sidecar.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: resourcesField(),
+			},
+		},
+		"object_storage_config": {
+			Type:        schema.TypeList,
+			Description: "ObjectStorageConfig configures object storage in Thanos.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: secretKeySelectorSchema(),
+			},
+		},
+		"listen_local": {
+			Type:        schema.TypeBool,
+			Description: "ListenLocal makes the Thanos sidecar listen on loopback, so that it does not bind against the Pod IP.",
+			Optional:    true,
+		},
+		"log_level": {
+			Type:        schema.TypeString,
+			Description: "LogLevel for Thanos sidecar to be configured with.",
+			Optional:    true,
+		},
+		"min_time": {
+			Type:        schema.TypeString,
+			Description: "MinTime for Thanos sidecar to be configured with.",
+			Optional:    true,
+		},
+	}
+}
+
+// prometheusStatefulSets selects the StatefulSets the operator creates for
+// the shards of the named Prometheus.
+func prometheusStatefulSets(name string) metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: "operator.prometheus.io/name=" + name,
+	}
+}
+
+func expandPrometheusSpec(p []interface{}) (*po_types.PrometheusSpec, error) {
+	obj := &po_types.PrometheusSpec{}
+	if len(p) == 0 || p[0] == nil {
+		return obj, nil
+	}
+	in := p[0].(map[string]interface{})
+
+	obj.Replicas = ptrToInt32(int32(in["replicas"].(int)))
+	obj.Shards = ptrToInt32(int32(in["shards"].(int)))
+	obj.Version = in["version"].(string)
+	if v, ok := in["image"].(string); ok && v != "" {
+		obj.Image = ptrToString(v)
+	}
+	obj.Retention = in["retention"].(string)
+	obj.RetentionSize = in["retention_size"].(string)
+	obj.ScrapeInterval = in["scrape_interval"].(string)
+	obj.EvaluationInterval = in["evaluation_interval"].(string)
+	obj.ExternalURL = in["external_url"].(string)
+	obj.RoutePrefix = in["route_prefix"].(string)
+	obj.LogLevel = in["log_level"].(string)
+	obj.ServiceAccountName = in["service_account_name"].(string)
+	obj.PriorityClassName = in["priority_class_name"].(string)
+
+	if v, ok := in["service_monitor_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.ServiceMonitorSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["service_monitor_namespace_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.ServiceMonitorNamespaceSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["pod_monitor_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.PodMonitorSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["pod_monitor_namespace_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.PodMonitorNamespaceSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["probe_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.ProbeSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["probe_namespace_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.ProbeNamespaceSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["rule_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.RuleSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["rule_namespace_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.RuleNamespaceSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["alerting"].([]interface{}); ok && len(v) > 0 {
+		alerting, err := expandAlertingSpec(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Alerting = alerting
+	}
+	if v, ok := in["remote_write"].([]interface{}); ok && len(v) > 0 {
+		rw, err := expandRemoteWriteSpecs(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.RemoteWrite = rw
+	}
+	if v, ok := in["remote_read"].([]interface{}); ok && len(v) > 0 {
+		rr, err := expandRemoteReadSpecs(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.RemoteRead = rr
+	}
+	if v, ok := in["storage"].([]interface{}); ok && len(v) > 0 {
+		storage, err := expandStorageSpec(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Storage = storage
+	}
+	if v, ok := in["resources"].([]interface{}); ok && len(v) > 0 {
+		resources, err := expandContainerResourceRequirements(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Resources = *resources
+	}
+	if v, ok := in["node_selector"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.NodeSelector = expandStringMap(v)
+	}
+	if v, ok := in["tolerations"].([]interface{}); ok && len(v) > 0 {
+		tolerations, err := expandTolerations(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Tolerations = tolerations
+	}
+	if v, ok := in["affinity"].([]interface{}); ok && len(v) > 0 {
+		obj.Affinity = expandAffinity(v)
+	}
+	if v, ok := in["security_context"].([]interface{}); ok && len(v) > 0 {
+		sc, err := expandPodSecurityContext(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.SecurityContext = sc
+	}
+	if v, ok := in["external_labels"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.ExternalLabels = expandStringMap(v)
+	}
+	if v, ok := in["thanos"].([]interface{}); ok && len(v) > 0 {
+		thanos, err := expandThanosSpec(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Thanos = thanos
+	}
+	return obj, nil
+}
+
+func flattenPrometheusSpec(spec po_types.PrometheusSpec) ([]interface{}, error) {
+	att := make(map[string]interface{})
+
+	att["replicas"] = 1
+	if spec.Replicas != nil {
+		att["replicas"] = int(*spec.Replicas)
+	}
+	att["shards"] = 1
+	if spec.Shards != nil {
+		att["shards"] = int(*spec.Shards)
+	}
+	att["version"] = spec.Version
+	if spec.Image != nil {
+		att["image"] = *spec.Image
+	}
+	att["retention"] = spec.Retention
+	att["retention_size"] = spec.RetentionSize
+	att["scrape_interval"] = spec.ScrapeInterval
+	att["evaluation_interval"] = spec.EvaluationInterval
+	att["external_url"] = spec.ExternalURL
+	att["route_prefix"] = spec.RoutePrefix
+	att["log_level"] = spec.LogLevel
+	att["service_account_name"] = spec.ServiceAccountName
+	att["priority_class_name"] = spec.PriorityClassName
+
+	if spec.ServiceMonitorSelector != nil {
+		att["service_monitor_selector"] = flattenLabelSelector(spec.ServiceMonitorSelector)
+	}
+	if spec.ServiceMonitorNamespaceSelector != nil {
+		att["service_monitor_namespace_selector"] = flattenLabelSelector(spec.ServiceMonitorNamespaceSelector)
+	}
+	if spec.PodMonitorSelector != nil {
+		att["pod_monitor_selector"] = flattenLabelSelector(spec.PodMonitorSelector)
+	}
+	if spec.PodMonitorNamespaceSelector != nil {
+		att["pod_monitor_namespace_selector"] = flattenLabelSelector(spec.PodMonitorNamespaceSelector)
+	}
+	if spec.ProbeSelector != nil {
+		att["probe_selector"] = flattenLabelSelector(spec.ProbeSelector)
+	}
+	if spec.ProbeNamespaceSelector != nil {
+		att["probe_namespace_selector"] = flattenLabelSelector(spec.ProbeNamespaceSelector)
+	}
+	if spec.RuleSelector != nil {
+		att["rule_selector"] = flattenLabelSelector(spec.RuleSelector)
+	}
+	if spec.RuleNamespaceSelector != nil {
+		att["rule_namespace_selector"] = flattenLabelSelector(spec.RuleNamespaceSelector)
+	}
+	if spec.Alerting != nil {
+		alerting, err := flattenAlertingSpec(spec.Alerting)
+		if err != nil {
+			return nil, err
+		}
+		att["alerting"] = alerting
+	}
+	att["remote_write"] = flattenRemoteWriteSpecs(spec.RemoteWrite)
+	att["remote_read"] = flattenRemoteReadSpecs(spec.RemoteRead)
+	if spec.Storage != nil {
+		att["storage"] = flattenStorageSpec(spec.Storage)
+	}
+	if len(spec.Resources.Limits) > 0 || len(spec.Resources.Requests) > 0 {
+		att["resources"] = flattenContainerResourceRequirements(spec.Resources)
+	}
+	att["node_selector"] = spec.NodeSelector
+	att["tolerations"] = flattenTolerations(spec.Tolerations)
+	if spec.Affinity != nil {
+		att["affinity"] = flattenAffinity(spec.Affinity)
+	}
+	if spec.SecurityContext != nil {
+		att["security_context"] = flattenPodSecurityContext(spec.SecurityContext)
+	}
+	att["external_labels"] = spec.ExternalLabels
+	if spec.Thanos != nil {
+		att["thanos"] = flattenThanosSpec(spec.Thanos)
+	}
+
+	return []interface{}{att}, nil
+}
+
+func expandThanosSpec(l []interface{}) (*po_types.ThanosSpec, error) {
+	obj := &po_types.ThanosSpec{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
+	if v, ok := in["image"].(string); ok && v != "" {
+		obj.Image = ptrToString(v)
+	}
+	if v, ok := in["version"].(string); ok && v != "" {
+		obj.Version = ptrToString(v)
+	}
+	if v, ok := in["resources"].([]interface{}); ok && len(v) > 0 {
+		resources, err := expandContainerResourceRequirements(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Resources = *resources
+	}
+	if v, ok := in["object_storage_config"].([]interface{}); ok && len(v) > 0 {
+		osc, err := expandSecretKeyRef(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.ObjectStorageConfig = osc
+	}
+	obj.ListenLocal = in["listen_local"].(bool)
+	obj.LogLevel = in["log_level"].(string)
+	obj.MinTime = in["min_time"].(string)
+	return obj, nil
+}
+
+func flattenThanosSpec(in *po_types.ThanosSpec) []interface{} {
+	att := make(map[string]interface{})
+	if in.Image != nil {
+		att["image"] = *in.Image
+	}
+	if in.Version != nil {
+		att["version"] = *in.Version
+	}
+	if len(in.Resources.Limits) > 0 || len(in.Resources.Requests) > 0 {
+		att["resources"] = flattenContainerResourceRequirements(in.Resources)
+	}
+	if in.ObjectStorageConfig != nil {
+		att["object_storage_config"] = flattenSecretKeyRef(in.ObjectStorageConfig)
+	}
+	att["listen_local"] = in.ListenLocal
+	att["log_level"] = in.LogLevel
+	att["min_time"] = in.MinTime
+	return []interface{}{att}
+}
@@ -0,0 +1,108 @@
+package po
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+var prometheusRuleKind = &objectKind{
+	kind: po_types.PrometheusRuleKind,
+	gvr:  po_types.SchemeGroupVersion.WithResource(po_types.PrometheusRuleName),
+	gvk:  po_types.SchemeGroupVersion.WithKind(po_types.PrometheusRuleKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types.PrometheusRule{ObjectMeta: metadata, Spec: *spec.(*po_types.PrometheusRuleSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandPrometheusRuleSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenPrometheusRuleSpec(obj.(*po_types.PrometheusRule).Spec)
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1().PrometheusRules(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types.PrometheusRule), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+}
+
+func resourcePoPrometheusRule() *schema.Resource {
+	return prometheusRuleKind.resource(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("prometheus rule", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Spec defines the content of the alerting and recording rules. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#prometheusrulespec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: prometheusRuleSpecSchema(),
+				},
+			},
+		},
+	})
+}
+
+func prometheusRuleSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"group": {
+			Type:        schema.TypeList,
+			Description: "Content of Prometheus rule file. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#rulegroup",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: ruleGroupSchema(),
+			},
+		},
+	}
+}
+
+func expandPrometheusRuleSpec(pr []interface{}) (*po_types.PrometheusRuleSpec, error) {
+	obj := &po_types.PrometheusRuleSpec{}
+	if len(pr) == 0 || pr[0] == nil {
+		return obj, nil
+	}
+	in := pr[0].(map[string]interface{})
+
+	if v, ok := in["group"].([]interface{}); ok && len(v) > 0 {
+		groups, err := expandRuleGroup(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Groups = groups
+	}
+	return obj, nil
+}
+
+func flattenPrometheusRuleSpec(spec po_types.PrometheusRuleSpec) ([]interface{}, error) {
+	att := make(map[string]interface{})
+
+	groups, err := flattenRuleGroup(spec.Groups)
+	if err != nil {
+		return nil, err
+	}
+	att["group"] = groups
+
+	return []interface{}{att}, nil
+}
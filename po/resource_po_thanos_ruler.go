@@ -0,0 +1,340 @@
+package po
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	pkgApi "k8s.io/apimachinery/pkg/types"
+)
+
+var thanosRulerKind = &objectKind{
+	kind: po_types.ThanosRulerKind,
+	gvr:  po_types.SchemeGroupVersion.WithResource(po_types.ThanosRulerName),
+	gvk:  po_types.SchemeGroupVersion.WithKind(po_types.ThanosRulerKind),
+	build: func(metadata metav1.ObjectMeta, spec interface{}) runtime.Object {
+		return &po_types.ThanosRuler{ObjectMeta: metadata, Spec: *spec.(*po_types.ThanosRulerSpec)}
+	},
+	expandSpec: func(spec []interface{}) (interface{}, error) {
+		return expandThanosRulerSpec(spec)
+	},
+	flattenSpec: func(obj runtime.Object) ([]interface{}, error) {
+		return flattenThanosRulerSpec(obj.(*po_types.ThanosRuler).Spec), nil
+	},
+	client: func(meta interface{}, namespace string) (objectClient, error) {
+		conn, err := meta.(KubeClientsets).MonitoringClientset()
+		if err != nil {
+			return objectClient{}, err
+		}
+		c := conn.MonitoringV1().ThanosRulers(namespace)
+		return objectClient{
+			get: func(ctx context.Context, name string, opts metav1.GetOptions) (runtime.Object, error) {
+				return c.Get(ctx, name, opts)
+			},
+			list: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+				return c.List(ctx, opts)
+			},
+			create: func(ctx context.Context, obj runtime.Object, opts metav1.CreateOptions) (runtime.Object, error) {
+				return c.Create(ctx, obj.(*po_types.ThanosRuler), opts)
+			},
+			patch: func(ctx context.Context, name string, pt pkgApi.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+				return c.Patch(ctx, name, pt, data, opts)
+			},
+			delete: c.Delete,
+		}, nil
+	},
+}
+
+func resourcePoThanosRuler() *schema.Resource {
+	return thanosRulerKind.resource(&schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"metadata": namespacedMetadataSchema("thanos ruler", true),
+			"spec": {
+				Type:        schema.TypeList,
+				Description: "Specification of the desired behavior of the ThanosRuler cluster. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#thanosrulerspec",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: thanosRulerSpecSchema(),
+				},
+			},
+		},
+	})
+}
+
+func thanosRulerSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"replicas": {
+			Type:         schema.TypeInt,
+			Description:  "Number of thanos ruler instances to deploy.",
+			Optional:     true,
+			Default:      1,
+			ValidateFunc: validateNonNegativeInteger,
+		},
+		"image": {
+			Type:        schema.TypeString,
+			Description: "Thanos container image URL.",
+			Optional:    true,
+		},
+		"query_endpoints": {
+			Type:        schema.TypeList,
+			Description: "QueryEndpoints defines Thanos querier endpoints from which to query metrics. Maps to the --query flag of thanos ruler.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"query_config": {
+			Type:        schema.TypeList,
+			Description: "Define configuration for connecting to thanos query instances. If this is defined, the query_endpoints field will be ignored.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: secretKeySelectorSchema(),
+			},
+		},
+		"alertmanagers_url": {
+			Type:        schema.TypeList,
+			Description: "Define URLs to send alerts to Alertmanager. For Thanos v0.10.0 and higher, alertmanagers_config should be used instead.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"alertmanagers_config": {
+			Type:        schema.TypeList,
+			Description: "Define configuration for connecting to alertmanager. Only available with thanos v0.10.0 and higher. If defined, alertmanagers_url is ignored.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: secretKeySelectorSchema(),
+			},
+		},
+		"object_storage_config": {
+			Type:        schema.TypeList,
+			Description: "ObjectStorageConfig configures object storage in Thanos.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: secretKeySelectorSchema(),
+			},
+		},
+		"rule_selector":           optionalLabelSelectorSchema("A label selector to select which PrometheusRules to mount for alerting and recording."),
+		"rule_namespace_selector": optionalLabelSelectorSchema("Namespaces to be selected for Rules discovery. If unspecified, only the same namespace as the ThanosRuler object is in is used."),
+		"retention": {
+			Type:        schema.TypeString,
+			Description: "Time duration ThanosRuler shall retain data for. Default is '24h'.",
+			Optional:    true,
+		},
+		"evaluation_interval": {
+			Type:        schema.TypeString,
+			Description: "Interval between consecutive evaluations.",
+			Optional:    true,
+		},
+		"labels": {
+			Type:        schema.TypeMap,
+			Description: "Labels configure the external label pairs to ThanosRuler.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"log_level": {
+			Type:        schema.TypeString,
+			Description: "Log level for ThanosRuler to be configured with.",
+			Optional:    true,
+		},
+		"paused": {
+			Type:        schema.TypeBool,
+			Description: "When a ThanosRuler deployment is paused, no actions except for deletion will be performed on the underlying objects.",
+			Optional:    true,
+		},
+		"listen_local": {
+			Type:        schema.TypeBool,
+			Description: "ListenLocal makes the Thanos ruler listen on loopback, so that it does not bind against the Pod IP.",
+			Optional:    true,
+		},
+		"service_account_name": {
+			Type:        schema.TypeString,
+			Description: "ServiceAccountName is the name of the ServiceAccount to use to run the Thanos Ruler Pods.",
+			Optional:    true,
+		},
+		"priority_class_name": {
+			Type:        schema.TypeString,
+			Description: "Priority class assigned to the Pods.",
+			Optional:    true,
+		},
+		"storage": {
+			Type:        schema.TypeList,
+			Description: "Storage spec to specify how storage shall be used. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#storagespec",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: storageSpecSchema(),
+			},
+		},
+		"resources": {
+			Type:        schema.TypeList,
+			Description: "Resources defines the resource requirements for single Pods.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: resourcesField(),
+			},
+		},
+		"node_selector": {
+			Type:        schema.TypeMap,
+			Description: "Define which Nodes the Pods are scheduled on.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"tolerations": {
+			Type:        schema.TypeList,
+			Description: "If specified, the pod's tolerations.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: tolerationFields(),
+			},
+		},
+		"affinity": {
+			Type:        schema.TypeList,
+			Description: "If specified, the pod's scheduling constraints.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: affinityFields(),
+			},
+		},
+		"security_context": {
+			Type:        schema.TypeList,
+			Description: "SecurityContext holds pod-level security attributes and common container settings.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: podSecurityContextFields(),
+			},
+		},
+	}
+}
+
+func expandThanosRulerSpec(p []interface{}) (*po_types.ThanosRulerSpec, error) {
+	obj := &po_types.ThanosRulerSpec{}
+	if len(p) == 0 || p[0] == nil {
+		return obj, nil
+	}
+	in := p[0].(map[string]interface{})
+	var err error
+
+	obj.Replicas = ptrToInt32(int32(in["replicas"].(int)))
+	obj.Image = in["image"].(string)
+	if v, ok := in["query_endpoints"].([]interface{}); ok && len(v) > 0 {
+		obj.QueryEndpoints = expandStringSlice(v)
+	}
+	if obj.QueryConfig, err = expandOptionalSecretKeyRef(in["query_config"]); err != nil {
+		return obj, err
+	}
+	if v, ok := in["alertmanagers_url"].([]interface{}); ok && len(v) > 0 {
+		obj.AlertManagersURL = expandStringSlice(v)
+	}
+	if obj.AlertManagersConfig, err = expandOptionalSecretKeyRef(in["alertmanagers_config"]); err != nil {
+		return obj, err
+	}
+	if obj.ObjectStorageConfig, err = expandOptionalSecretKeyRef(in["object_storage_config"]); err != nil {
+		return obj, err
+	}
+	if v, ok := in["rule_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.RuleSelector = expandLabelSelector(v)
+	}
+	if v, ok := in["rule_namespace_selector"].([]interface{}); ok && len(v) > 0 {
+		obj.RuleNamespaceSelector = expandLabelSelector(v)
+	}
+	obj.Retention = in["retention"].(string)
+	obj.EvaluationInterval = in["evaluation_interval"].(string)
+	if v, ok := in["labels"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.Labels = expandStringMap(v)
+	}
+	obj.LogLevel = in["log_level"].(string)
+	obj.Paused = in["paused"].(bool)
+	obj.ListenLocal = in["listen_local"].(bool)
+	obj.ServiceAccountName = in["service_account_name"].(string)
+	obj.PriorityClassName = in["priority_class_name"].(string)
+
+	if v, ok := in["storage"].([]interface{}); ok && len(v) > 0 {
+		storage, err := expandStorageSpec(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Storage = storage
+	}
+	if v, ok := in["resources"].([]interface{}); ok && len(v) > 0 {
+		resources, err := expandContainerResourceRequirements(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Resources = *resources
+	}
+	if v, ok := in["node_selector"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.NodeSelector = expandStringMap(v)
+	}
+	if v, ok := in["tolerations"].([]interface{}); ok && len(v) > 0 {
+		tolerations, err := expandTolerations(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Tolerations = tolerations
+	}
+	if v, ok := in["affinity"].([]interface{}); ok && len(v) > 0 {
+		obj.Affinity = expandAffinity(v)
+	}
+	if v, ok := in["security_context"].([]interface{}); ok && len(v) > 0 {
+		sc, err := expandPodSecurityContext(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.SecurityContext = sc
+	}
+	return obj, nil
+}
+
+func flattenThanosRulerSpec(spec po_types.ThanosRulerSpec) []interface{} {
+	att := make(map[string]interface{})
+
+	att["replicas"] = 1
+	if spec.Replicas != nil {
+		att["replicas"] = int(*spec.Replicas)
+	}
+	att["image"] = spec.Image
+	att["query_endpoints"] = spec.QueryEndpoints
+	att["query_config"] = flattenOptionalSecretKeyRef(spec.QueryConfig)
+	att["alertmanagers_url"] = spec.AlertManagersURL
+	att["alertmanagers_config"] = flattenOptionalSecretKeyRef(spec.AlertManagersConfig)
+	att["object_storage_config"] = flattenOptionalSecretKeyRef(spec.ObjectStorageConfig)
+	if spec.RuleSelector != nil {
+		att["rule_selector"] = flattenLabelSelector(spec.RuleSelector)
+	}
+	if spec.RuleNamespaceSelector != nil {
+		att["rule_namespace_selector"] = flattenLabelSelector(spec.RuleNamespaceSelector)
+	}
+	att["retention"] = spec.Retention
+	att["evaluation_interval"] = spec.EvaluationInterval
+	att["labels"] = spec.Labels
+	att["log_level"] = spec.LogLevel
+	att["paused"] = spec.Paused
+	att["listen_local"] = spec.ListenLocal
+	att["service_account_name"] = spec.ServiceAccountName
+	att["priority_class_name"] = spec.PriorityClassName
+
+	if spec.Storage != nil {
+		att["storage"] = flattenStorageSpec(spec.Storage)
+	}
+	if len(spec.Resources.Limits) > 0 || len(spec.Resources.Requests) > 0 {
+		att["resources"] = flattenContainerResourceRequirements(spec.Resources)
+	}
+	att["node_selector"] = spec.NodeSelector
+	att["tolerations"] = flattenTolerations(spec.Tolerations)
+	if spec.Affinity != nil {
+		att["affinity"] = flattenAffinity(spec.Affinity)
+	}
+	if spec.SecurityContext != nil {
+		att["security_context"] = flattenPodSecurityContext(spec.SecurityContext)
+	}
+
+	return []interface{}{att}
+}
@@ -1,6 +1,10 @@
 package po
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func endpointSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
@@ -96,6 +100,155 @@ func endpointSchema() map[string]*schema.Schema {
 	}
 }
 
+func podMetricsEndpointSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"port": {
+			Type:        schema.TypeString,
+			Description: "Name of the pod port this endpoint refers to. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmetricsendpoint",
+			Optional:    true,
+		},
+		"path": {
+			Type:        schema.TypeString,
+			Description: "HTTP path to scrape for metrics. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmetricsendpoint",
+			Optional:    true,
+		},
+		"scheme": {
+			Type:        schema.TypeString,
+			Description: "HTTP scheme to use for scraping.",
+			Optional:    true,
+		},
+		"interval": {
+			Type:        schema.TypeString,
+			Description: "Interval at which metrics should be scraped.",
+			Optional:    true,
+		},
+		"scrape_timeout": {
+			Type:        schema.TypeString,
+			Description: "Timeout after which the scrape is ended.",
+			Optional:    true,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "TLS configuration to use when scraping the endpoint. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#podmetricsendpointtlsconfig",
+			Elem: &schema.Resource{
+				Schema: safeTLSConfigSchema(),
+			},
+		},
+		"bearer_token_secret": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Secret to mount to read bearer token for scraping targets. The secret needs to be in the same namespace as the pod monitor and accessible by the Prometheus Operator.",
+			Elem: &schema.Resource{
+				Schema: secretKeySelectorSchema(),
+			},
+		},
+		"honor_labels": {
+			Type:        schema.TypeBool,
+			Description: "HonorLabels chooses the metric's labels on collisions with target labels.",
+			Optional:    true,
+		},
+		"honor_timestamps": {
+			Type:        schema.TypeBool,
+			Description: "HonorTimestamps controls whether Prometheus respects the timestamps present in scraped data.",
+			Optional:    true,
+			Default:     true,
+		},
+		"basic_auth": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "BasicAuth allow an endpoint to authenticate over basic authentication More info: https://prometheus.io/docs/operating/configuration/#endpoints",
+			Elem: &schema.Resource{
+				Schema: basicAuthSchema(),
+			},
+		},
+		"metric_relabelings": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "MetricRelabelConfigs to apply to samples before ingestion.",
+			Elem: &schema.Resource{
+				Schema: relabelConfigSchema(),
+			},
+		},
+		"relabelings": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "RelabelConfigs to apply to samples before scraping. More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config",
+			Elem: &schema.Resource{
+				Schema: relabelConfigSchema(),
+			},
+		},
+		"proxy_url": {
+			Type:        schema.TypeString,
+			Description: "ProxyURL eg http://proxyserver:2195 Directs scrapes to proxy through this endpoint.",
+			Optional:    true,
+		},
+	}
+}
+
+func ruleGroupSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of the rule group. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#rulegroup",
+			Required:    true,
+		},
+		"interval": {
+			Type:        schema.TypeString,
+			Description: "How often rules in the group are evaluated.",
+			Optional:    true,
+		},
+		"rule": {
+			Type:        schema.TypeList,
+			Description: "List of alerting and recording rules. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#rule",
+			Required:    true,
+			Elem: &schema.Resource{
+				Schema: ruleSchema(),
+			},
+		},
+	}
+}
+
+func ruleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"alert": {
+			Type:        schema.TypeString,
+			Description: "The name of the alert. Must be a valid metric name. Mutually exclusive with record.",
+			Optional:    true,
+		},
+		"record": {
+			Type:        schema.TypeString,
+			Description: "The name of the time series to output to. Must be a valid metric name. Mutually exclusive with alert.",
+			Optional:    true,
+		},
+		"expr": {
+			Type:        schema.TypeString,
+			Description: "The PromQL expression to evaluate.",
+			Required:    true,
+		},
+		"for": {
+			Type:        schema.TypeString,
+			Description: "Alerts are considered firing once they have been returned for this long.",
+			Optional:    true,
+		},
+		"labels": {
+			Type:        schema.TypeMap,
+			Description: "Labels to add or overwrite for each alert or recorded time series.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"annotations": {
+			Type:        schema.TypeMap,
+			Description: "Annotations to add to each alert. Only valid for alerting rules.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
 func relabelConfigSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"separator": {
@@ -232,6 +385,16 @@ func tlsConfigSchema() map[string]*schema.Schema {
 	}
 }
 
+// safeTLSConfigSchema is the subset of tlsConfigSchema that does not reference
+// files in the Prometheus container, as used by PodMonitors and Probes.
+func safeTLSConfigSchema() map[string]*schema.Schema {
+	s := tlsConfigSchema()
+	delete(s, "ca_file")
+	delete(s, "cert_file")
+	delete(s, "key_file")
+	return s
+}
+
 func secretOrConfigMapSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"secret": {
@@ -322,3 +485,385 @@ func labelSelectorFields(updatable bool) map[string]*schema.Schema {
 		},
 	}
 }
+
+func alertingSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"alertmanagers": {
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: "AlertmanagerEndpoints Prometheus should fire alerts against. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#alertmanagerendpoints",
+			Elem: &schema.Resource{
+				Schema: alertmanagerEndpointsSchema(),
+			},
+		},
+	}
+}
+
+func alertmanagerEndpointsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"namespace": {
+			Type:        schema.TypeString,
+			Description: "Namespace of Endpoints object.",
+			Required:    true,
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name of Endpoints object in Namespace.",
+			Required:    true,
+		},
+		"port": {
+			Type:        schema.TypeString,
+			Description: "Port the Alertmanager API is exposed on, either as a port name or number.",
+			Required:    true,
+		},
+		"scheme": {
+			Type:        schema.TypeString,
+			Description: "Scheme to use when firing alerts.",
+			Optional:    true,
+		},
+		"path_prefix": {
+			Type:        schema.TypeString,
+			Description: "Prefix for the HTTP path alerts are pushed to.",
+			Optional:    true,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "TLS Config to use for alertmanager connection. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#tlsconfig",
+			Elem: &schema.Resource{
+				Schema: tlsConfigSchema(),
+			},
+		},
+		"bearer_token_file": {
+			Type:        schema.TypeString,
+			Description: "BearerTokenFile to read from filesystem to use when authenticating to Alertmanager.",
+			Optional:    true,
+		},
+		"api_version": {
+			Type:        schema.TypeString,
+			Description: "Version of the Alertmanager API that Prometheus uses to send alerts. It can be \"v1\" or \"v2\".",
+			Optional:    true,
+		},
+		"timeout": {
+			Type:        schema.TypeString,
+			Description: "Timeout is a per-target Alertmanager timeout when pushing alerts.",
+			Optional:    true,
+		},
+	}
+}
+
+func remoteWriteSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"url": {
+			Type:        schema.TypeString,
+			Description: "The URL of the endpoint to send samples to.",
+			Required:    true,
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the remote write queue, must be unique if specified.",
+			Optional:    true,
+		},
+		"remote_timeout": {
+			Type:        schema.TypeString,
+			Description: "Timeout for requests to the remote write endpoint.",
+			Optional:    true,
+		},
+		"headers": {
+			Type:        schema.TypeMap,
+			Description: "Custom HTTP headers to be sent along with each remote write request.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"write_relabel_configs": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The list of remote write relabel configurations. More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config",
+			Elem: &schema.Resource{
+				Schema: relabelConfigSchema(),
+			},
+		},
+		"basic_auth": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "BasicAuth for the URL.",
+			Elem: &schema.Resource{
+				Schema: basicAuthSchema(),
+			},
+		},
+		"bearer_token_file": {
+			Type:        schema.TypeString,
+			Description: "File to read bearer token for remote write.",
+			Optional:    true,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "TLS Config to use for remote write. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#tlsconfig",
+			Elem: &schema.Resource{
+				Schema: tlsConfigSchema(),
+			},
+		},
+		"proxy_url": {
+			Type:        schema.TypeString,
+			Description: "Optional ProxyURL.",
+			Optional:    true,
+		},
+		"queue_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "QueueConfig allows tuning of the remote write queue parameters. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#queueconfig",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"capacity": {
+						Type:        schema.TypeInt,
+						Description: "Capacity is the number of samples to buffer per shard before we start dropping them.",
+						Optional:    true,
+					},
+					"min_shards": {
+						Type:        schema.TypeInt,
+						Description: "MinShards is the minimum number of shards, i.e. amount of concurrency.",
+						Optional:    true,
+					},
+					"max_shards": {
+						Type:        schema.TypeInt,
+						Description: "MaxShards is the maximum number of shards, i.e. amount of concurrency.",
+						Optional:    true,
+					},
+					"max_samples_per_send": {
+						Type:        schema.TypeInt,
+						Description: "MaxSamplesPerSend is the maximum number of samples per send.",
+						Optional:    true,
+					},
+					"batch_send_deadline": {
+						Type:        schema.TypeString,
+						Description: "BatchSendDeadline is the maximum time a sample will wait in buffer.",
+						Optional:    true,
+					},
+					"max_retries": {
+						Type:        schema.TypeInt,
+						Description: "MaxRetries is the maximum number of times to retry a batch on recoverable errors.",
+						Optional:    true,
+					},
+					"min_backoff": {
+						Type:        schema.TypeString,
+						Description: "MinBackoff is the initial retry delay. Gets doubled for every retry.",
+						Optional:    true,
+					},
+					"max_backoff": {
+						Type:        schema.TypeString,
+						Description: "MaxBackoff is the maximum retry delay.",
+						Optional:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func remoteReadSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"url": {
+			Type:        schema.TypeString,
+			Description: "The URL of the endpoint to query from.",
+			Required:    true,
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the remote read queue, must be unique if specified.",
+			Optional:    true,
+		},
+		"required_matchers": {
+			Type:        schema.TypeMap,
+			Description: "An optional list of equality matchers which have to be present in a selector to query the remote read endpoint.",
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"remote_timeout": {
+			Type:        schema.TypeString,
+			Description: "Timeout for requests to the remote read endpoint.",
+			Optional:    true,
+		},
+		"read_recent": {
+			Type:        schema.TypeBool,
+			Description: "Whether reads should be made for queries for time ranges that the local storage should have complete data for.",
+			Optional:    true,
+		},
+		"basic_auth": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "BasicAuth for the URL.",
+			Elem: &schema.Resource{
+				Schema: basicAuthSchema(),
+			},
+		},
+		"bearer_token_file": {
+			Type:        schema.TypeString,
+			Description: "File to read bearer token for remote read.",
+			Optional:    true,
+		},
+		"tls_config": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "TLS Config to use for remote read. More info: https://github.com/coreos/prometheus-operator/blob/master/Documentation/api.md#tlsconfig",
+			Elem: &schema.Resource{
+				Schema: tlsConfigSchema(),
+			},
+		},
+		"proxy_url": {
+			Type:        schema.TypeString,
+			Description: "Optional ProxyURL.",
+			Optional:    true,
+		},
+	}
+}
+
+func storageSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"disable_mount_sub_path": {
+			Type:        schema.TypeBool,
+			Description: "Deprecated: subPath usage will be disabled by default in a future release, this option will become unnecessary.",
+			Optional:    true,
+		},
+		"empty_dir": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "EmptyDirVolumeSource to be used by the StatefulSet. More info: https://kubernetes.io/docs/concepts/storage/volumes/#emptydir",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"medium": {
+						Type:         schema.TypeString,
+						Description:  "What type of storage medium should back this directory. The default is \"\" which means to use the node's default medium.",
+						Optional:     true,
+						ValidateFunc: validateAttributeValueIsIn([]string{"", "Memory"}),
+					},
+					"size_limit": {
+						Type:         schema.TypeString,
+						Description:  "Total amount of local storage required for this EmptyDir volume.",
+						Optional:     true,
+						ValidateFunc: validateResourceQuantity,
+					},
+				},
+			},
+		},
+		"volume_claim_template": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "A PVC spec to be used by the StatefulSet.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"metadata": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						MaxItems:    1,
+						Description: "EmbeddedMetadata contains metadata relevant to an EmbeddedResource.",
+						Elem: &schema.Resource{
+							Schema: embeddedObjectMetadataSchema(),
+						},
+					},
+					"spec": {
+						Type:        schema.TypeList,
+						Required:    true,
+						MaxItems:    1,
+						Description: "Spec defines the desired characteristics of a volume requested by a pod author. More info: https://kubernetes.io/docs/concepts/storage/persistent-volumes#persistentvolumeclaims",
+						Elem: &schema.Resource{
+							Schema: persistentVolumeClaimSpecFields(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func embeddedObjectMetadataSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "Name must be unique within a namespace.",
+			Optional:    true,
+		},
+		"labels": {
+			Type:         schema.TypeMap,
+			Description:  "Map of string keys and values that can be used to organize and categorize (scope and select) objects.",
+			Optional:     true,
+			Elem:         &schema.Schema{Type: schema.TypeString},
+			ValidateFunc: validateLabels,
+		},
+		"annotations": {
+			Type:         schema.TypeMap,
+			Description:  "Annotations is an unstructured key value map stored with a resource that may be set by external tools to store and retrieve arbitrary metadata.",
+			Optional:     true,
+			Elem:         &schema.Schema{Type: schema.TypeString},
+			ValidateFunc: validateAnnotations,
+		},
+	}
+}
+
+func optionalLabelSelectorSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		Description: description,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: labelSelectorFields(true),
+		},
+	}
+}
+
+// datasourceSchemaFromResourceSchema turns a resource schema into a fully
+// computed one so data sources can expose the same shape as their resource.
+func datasourceSchemaFromResourceSchema(rs map[string]*schema.Schema) map[string]*schema.Schema {
+	ds := make(map[string]*schema.Schema, len(rs))
+	for k, v := range rs {
+		dv := &schema.Schema{
+			Type:        v.Type,
+			Description: v.Description,
+			Sensitive:   v.Sensitive,
+			Computed:    true,
+		}
+		switch elem := v.Elem.(type) {
+		case *schema.Resource:
+			dv.Elem = &schema.Resource{
+				Schema: datasourceSchemaFromResourceSchema(elem.Schema),
+			}
+		case *schema.Schema:
+			dv.Elem = elem
+		}
+		ds[k] = dv
+	}
+	return ds
+}
+
+func dataSourceNamespacedMetadataSchema(objectName string) *schema.Schema {
+	fields := datasourceSchemaFromResourceSchema(metadataFields(objectName))
+	fields["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: fmt.Sprintf("Name of the %s. More info: http://kubernetes.io/docs/user-guide/identifiers#names", objectName),
+		Required:    true,
+	}
+	fields["namespace"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: fmt.Sprintf("Namespace of the %s. Defaults to the namespace of the current kubeconfig context.", objectName),
+		Optional:    true,
+		Computed:    true,
+	}
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: fmt.Sprintf("Standard %s's metadata. More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata", objectName),
+		Required:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: fields,
+		},
+	}
+}
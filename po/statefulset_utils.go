@@ -0,0 +1,76 @@
+package po
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// statefulSetInputHash is the annotation in which the operator records the
+// hash of the inputs a StatefulSet was last generated from.
+const statefulSetInputHash = "prometheus-operator-input-hash"
+
+// statefulSetRevision is the state of a StatefulSet before its owner is
+// updated, used to tell whether the operator has reconciled the update yet.
+type statefulSetRevision struct {
+	inputHash  string
+	generation int64
+}
+
+// snapshotStatefulSets records the revision of every StatefulSet matching
+// opts in namespace.
+func snapshotStatefulSets(ctx context.Context, conn *kubernetes.Clientset, namespace string, opts metav1.ListOptions) (map[string]statefulSetRevision, error) {
+	sets, err := conn.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make(map[string]statefulSetRevision, len(sets.Items))
+	for _, ss := range sets.Items {
+		revisions[ss.Name] = statefulSetRevision{
+			inputHash:  ss.Annotations[statefulSetInputHash],
+			generation: ss.Generation,
+		}
+	}
+	return revisions, nil
+}
+
+// waitForStatefulSetsRollout blocks until exactly `expected` StatefulSets
+// matching opts exist in namespace and every one of them has rolled out its
+// current revision with `replicas` replicas ready. StatefulSets recorded in
+// `before` must first be picked up by the operator, i.e. have their input
+// hash or generation move past the recorded one; pass nil on create.
+func waitForStatefulSetsRollout(ctx context.Context, conn *kubernetes.Clientset, namespace string, opts metav1.ListOptions, expected int, replicas int32, before map[string]statefulSetRevision, timeout time.Duration) error {
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		sets, err := conn.AppsV1().StatefulSets(namespace).List(ctx, opts)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if len(sets.Items) != expected {
+			return resource.RetryableError(fmt.Errorf("Waiting for %d statefulset(s) in %s, found %d", expected, namespace, len(sets.Items)))
+		}
+		for _, ss := range sets.Items {
+			if prev, ok := before[ss.Name]; ok && ss.Annotations[statefulSetInputHash] == prev.inputHash && ss.Generation <= prev.generation {
+				return resource.RetryableError(fmt.Errorf("Waiting for the operator to update statefulset %s/%s", ss.Namespace, ss.Name))
+			}
+			if ss.Generation > ss.Status.ObservedGeneration {
+				return resource.RetryableError(fmt.Errorf("Waiting for statefulset %s/%s spec update to be observed", ss.Namespace, ss.Name))
+			}
+			if ss.Spec.Replicas == nil || *ss.Spec.Replicas != replicas {
+				return resource.RetryableError(fmt.Errorf("Waiting for statefulset %s/%s to be scaled to %d replicas", ss.Namespace, ss.Name, replicas))
+			}
+			if ss.Status.UpdateRevision != "" && ss.Status.CurrentRevision != ss.Status.UpdateRevision {
+				return resource.RetryableError(fmt.Errorf("Waiting for statefulset %s/%s rollout to finish: %d of %d replicas updated", ss.Namespace, ss.Name, ss.Status.UpdatedReplicas, replicas))
+			}
+			if ss.Status.ReadyReplicas != replicas || ss.Status.Replicas != replicas {
+				return resource.RetryableError(fmt.Errorf("Waiting for statefulset %s/%s rollout to finish: %d of %d replicas ready", ss.Namespace, ss.Name, ss.Status.ReadyReplicas, replicas))
+			}
+			log.Printf("[DEBUG] Statefulset %s/%s has %d of %d replicas ready", ss.Namespace, ss.Name, ss.Status.ReadyReplicas, replicas)
+		}
+		return nil
+	})
+}
@@ -1,11 +1,16 @@
 package po
 
 import (
-	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func expandRuleGroup(groups []interface{}) ([]po_types.RuleGroup, error) {
@@ -21,7 +26,7 @@ func expandRuleGroup(groups []interface{}) ([]po_types.RuleGroup, error) {
 		if interval, ok := in["interval"]; ok {
 			obj[i].Interval = interval.(string)
 		}
-		if v, ok := in["rules"].([]interface{}); ok && len(v) > 0 {
+		if v, ok := in["rule"].([]interface{}); ok && len(v) > 0 {
 			rules, err := expandRules(v)
 			if err != nil {
 				return obj, err
@@ -38,7 +43,7 @@ func flattenRuleGroup(in []po_types.RuleGroup) ([]interface{}, error) {
 		out := make(map[string]interface{})
 		out["name"] = v.Name
 		out["interval"] = v.Interval
-		out["rules"] = flattenRules(v.Rules)
+		out["rule"] = flattenRules(v.Rules)
 		att[i] = out
 	}
 	return att, nil
@@ -56,10 +61,13 @@ func expandRules(rules []interface{}) ([]po_types.Rule, error) {
 			obj[i].Alert = alert.(string)
 		}
 		if expr, ok := in["expr"]; ok {
-			if num, err := strconv.Atoi(expr.(string)); err == nil {
-				obj[i].Expr = intstr.FromInt(num)
+			// Only a canonical int32 survives the round trip through
+			// intstr, anything else ("01", "+1", " 1") stays a string.
+			s := expr.(string)
+			if num, err := strconv.ParseInt(s, 10, 32); err == nil && strconv.FormatInt(num, 10) == s {
+				obj[i].Expr = intstr.FromInt(int(num))
 			} else {
-				obj[i].Expr = intstr.FromString(expr.(string))
+				obj[i].Expr = intstr.FromString(s)
 			}
 		}
 		if f, ok := in["for"]; ok {
@@ -74,6 +82,9 @@ func expandRules(rules []interface{}) ([]po_types.Rule, error) {
 		if a, ok := in["annotations"].(map[string]interface{}); ok && len(a) > 0 {
 			obj[i].Annotations = expandStringMap(in["annotations"].(map[string]interface{}))
 		}
+		if (obj[i].Alert == "") == (obj[i].Record == "") {
+			return obj, fmt.Errorf("rule %d: exactly one of alert or record must be set", i)
+		}
 	}
 	return obj, nil
 }
@@ -95,7 +106,7 @@ func flattenRules(in []po_types.Rule) []interface{} {
 		out["for"] = v.For
 		out["labels"] = v.Labels
 		out["annotations"] = v.Annotations
-		out["expr"] = v.Expr.StrVal
+		out["expr"] = v.Expr.String()
 		att[i] = out
 	}
 	return att
@@ -165,6 +176,12 @@ func expandAlertmanagersEndpoints(endpoints []interface{}) ([]po_types.Alertmana
 		if btf, ok := in["bearer_token_file"]; ok {
 			obj[i].BearerTokenFile = btf.(string)
 		}
+		if av, ok := in["api_version"]; ok {
+			obj[i].APIVersion = av.(string)
+		}
+		if t, ok := in["timeout"].(string); ok && t != "" {
+			obj[i].Timeout = ptrToString(t)
+		}
 	}
 	return obj, nil
 }
@@ -175,13 +192,17 @@ func flattenAlertmanagersEndpoints(in []po_types.AlertmanagerEndpoints) ([]inter
 		e := make(map[string]interface{})
 		e["name"] = v.Name
 		e["namespace"] = v.Namespace
-		e["port"] = v.Port
+		e["port"] = v.Port.String()
 		e["path_prefix"] = v.PathPrefix
 		e["scheme"] = v.Scheme
 		if v.TLSConfig != nil {
 			e["tls_config"] = flattenTLSConfig(v.TLSConfig)
 		}
 		e["bearer_token_file"] = v.BearerTokenFile
+		e["api_version"] = v.APIVersion
+		if v.Timeout != nil {
+			e["timeout"] = *v.Timeout
+		}
 		att[i] = e
 	}
 	return att, nil
@@ -213,6 +234,24 @@ func flattenNamespaceSelector(in *po_types.NamespaceSelector) []interface{} {
 	return []interface{}{att}
 }
 
+// flattenOptionalLabelSelector flattens the selector of an optional block,
+// leaving the block out when the selector is empty.
+func flattenOptionalLabelSelector(in *metav1.LabelSelector) []interface{} {
+	if in == nil || (len(in.MatchLabels) == 0 && len(in.MatchExpressions) == 0) {
+		return []interface{}{}
+	}
+	return flattenLabelSelector(in)
+}
+
+// flattenOptionalNamespaceSelector flattens the namespace selector of an
+// optional block, leaving the block out when the selector is empty.
+func flattenOptionalNamespaceSelector(in *po_types.NamespaceSelector) []interface{} {
+	if in == nil || (!in.Any && len(in.MatchNames) == 0) {
+		return []interface{}{}
+	}
+	return flattenNamespaceSelector(in)
+}
+
 func expandRelabelConfig(conf []interface{}) ([]*po_types.RelabelConfig, error) {
 	obj := make([]*po_types.RelabelConfig, len(conf))
 	if len(conf) == 0 || conf[0] == nil {
@@ -338,7 +377,32 @@ func expandTLSConfig(l []interface{}) (*po_types.TLSConfig, error) {
 		return obj, nil
 	}
 	in := l[0].(map[string]interface{})
+	safe, err := expandSafeTLSConfig(l)
+	if err != nil {
+		return obj, err
+	}
+	obj.SafeTLSConfig = *safe
 	obj.CAFile = in["ca_file"].(string)
+	obj.CertFile = in["cert_file"].(string)
+	obj.KeyFile = in["key_file"].(string)
+
+	return obj, nil
+}
+
+func flattenTLSConfig(in *po_types.TLSConfig) []interface{} {
+	att := flattenSafeTLSConfig(&in.SafeTLSConfig)[0].(map[string]interface{})
+	att["ca_file"] = in.CAFile
+	att["cert_file"] = in.CertFile
+	att["key_file"] = in.KeyFile
+	return []interface{}{att}
+}
+
+func expandSafeTLSConfig(l []interface{}) (*po_types.SafeTLSConfig, error) {
+	obj := &po_types.SafeTLSConfig{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
 	if v, ok := in["ca"].([]interface{}); ok && len(v) > 0 {
 		ca, err := expandSecretOrConfigMap(v)
 		if err != nil {
@@ -346,7 +410,6 @@ func expandTLSConfig(l []interface{}) (*po_types.TLSConfig, error) {
 		}
 		obj.CA = *ca
 	}
-	obj.CertFile = in["cert_file"].(string)
 	if v, ok := in["cert"].([]interface{}); ok && len(v) > 0 {
 		cert, err := expandSecretOrConfigMap(v)
 		if err != nil {
@@ -354,7 +417,6 @@ func expandTLSConfig(l []interface{}) (*po_types.TLSConfig, error) {
 		}
 		obj.Cert = *cert
 	}
-	obj.KeyFile = in["key_file"].(string)
 	if v, ok := in["key_secret"].([]interface{}); ok && len(v) > 0 {
 		ks, err := expandSecretKeyRef(v)
 		if err != nil {
@@ -368,17 +430,14 @@ func expandTLSConfig(l []interface{}) (*po_types.TLSConfig, error) {
 	return obj, nil
 }
 
-func flattenTLSConfig(in *po_types.TLSConfig) []interface{} {
+func flattenSafeTLSConfig(in *po_types.SafeTLSConfig) []interface{} {
 	att := make(map[string]interface{})
-	att["ca_file"] = in.CAFile
 	if in.CA.Secret != nil || in.CA.ConfigMap != nil {
 		att["ca"] = flattenSecretOrConfigMap(&in.CA)
 	}
-	att["cert_file"] = in.CertFile
 	if in.Cert.Secret != nil || in.Cert.ConfigMap != nil {
 		att["cert"] = flattenSecretOrConfigMap(&in.Cert)
 	}
-	att["key_file"] = in.KeyFile
 	if in.KeySecret != nil {
 		att["key_secret"] = flattenSecretKeyRef(in.KeySecret)
 	}
@@ -492,3 +551,357 @@ func flattenEndpoints(in []po_types.Endpoint) ([]interface{}, error) {
 	}
 	return att, nil
 }
+
+func expandPodMetricsEndpoints(endpoints []interface{}) ([]po_types.PodMetricsEndpoint, error) {
+	if len(endpoints) == 0 {
+		return []po_types.PodMetricsEndpoint{}, nil
+	}
+	obj := make([]po_types.PodMetricsEndpoint, len(endpoints))
+	for i, e := range endpoints {
+		in := e.(map[string]interface{})
+		if port, ok := in["port"]; ok {
+			obj[i].Port = port.(string)
+		}
+		if path, ok := in["path"]; ok {
+			obj[i].Path = path.(string)
+		}
+		if scheme, ok := in["scheme"]; ok {
+			obj[i].Scheme = scheme.(string)
+		}
+		if interval, ok := in["interval"]; ok {
+			obj[i].Interval = interval.(string)
+		}
+		if st, ok := in["scrape_timeout"]; ok {
+			obj[i].ScrapeTimeout = st.(string)
+		}
+		if tls, ok := in["tls_config"].([]interface{}); ok && len(tls) > 0 {
+			tls, err := expandSafeTLSConfig(tls)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].TLSConfig = &po_types.PodMetricsEndpointTLSConfig{SafeTLSConfig: *tls}
+		}
+		if bts, ok := in["bearer_token_secret"].([]interface{}); ok && len(bts) > 0 {
+			s, err := expandSecretKeyRef(bts)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].BearerTokenSecret = *s
+		}
+		if hl, ok := in["honor_labels"]; ok {
+			obj[i].HonorLabels = hl.(bool)
+		}
+		if ht, ok := in["honor_timestamps"]; ok {
+			obj[i].HonorTimestamps = ptrToBool(ht.(bool))
+		}
+		if ba, ok := in["basic_auth"].([]interface{}); ok && len(ba) > 0 {
+			ba, err := expandBasicAuth(ba)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].BasicAuth = ba
+		}
+		if mrl, ok := in["metric_relabelings"].([]interface{}); ok && len(mrl) > 0 {
+			c, err := expandRelabelConfig(mrl)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].MetricRelabelConfigs = c
+		}
+		if re, ok := in["relabelings"].([]interface{}); ok && len(re) > 0 {
+			c, err := expandRelabelConfig(re)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].RelabelConfigs = c
+		}
+		if pu, ok := in["proxy_url"].(string); ok && pu != "" {
+			obj[i].ProxyURL = ptrToString(pu)
+		}
+	}
+	return obj, nil
+}
+
+func flattenPodMetricsEndpoints(in []po_types.PodMetricsEndpoint) ([]interface{}, error) {
+	att := make([]interface{}, len(in))
+	for i, v := range in {
+		e := make(map[string]interface{})
+		e["port"] = v.Port
+		e["path"] = v.Path
+		e["scheme"] = v.Scheme
+		e["interval"] = v.Interval
+		e["scrape_timeout"] = v.ScrapeTimeout
+		if v.TLSConfig != nil {
+			e["tls_config"] = flattenSafeTLSConfig(&v.TLSConfig.SafeTLSConfig)
+		}
+		if v.BearerTokenSecret.Key != "" || v.BearerTokenSecret.Name != "" {
+			e["bearer_token_secret"] = flattenSecretKeyRef(&v.BearerTokenSecret)
+		}
+		e["honor_labels"] = v.HonorLabels
+		if v.HonorTimestamps != nil {
+			e["honor_timestamps"] = *v.HonorTimestamps
+		}
+		if v.BasicAuth != nil {
+			e["basic_auth"] = flattenBasicAuth(v.BasicAuth)
+		}
+		e["metric_relabelings"] = flattenRelabelConfig(v.MetricRelabelConfigs)
+		e["relabelings"] = flattenRelabelConfig(v.RelabelConfigs)
+		if v.ProxyURL != nil {
+			e["proxy_url"] = *v.ProxyURL
+		}
+		att[i] = e
+	}
+	return att, nil
+}
+
+func expandRemoteWriteSpecs(specs []interface{}) ([]po_types.RemoteWriteSpec, error) {
+	if len(specs) == 0 {
+		return []po_types.RemoteWriteSpec{}, nil
+	}
+	obj := make([]po_types.RemoteWriteSpec, len(specs))
+	for i, e := range specs {
+		in := e.(map[string]interface{})
+		obj[i].URL = in["url"].(string)
+		obj[i].Name = in["name"].(string)
+		obj[i].RemoteTimeout = in["remote_timeout"].(string)
+		if h, ok := in["headers"].(map[string]interface{}); ok && len(h) > 0 {
+			obj[i].Headers = expandStringMap(h)
+		}
+		if wrc, ok := in["write_relabel_configs"].([]interface{}); ok && len(wrc) > 0 {
+			c, err := expandRelabelConfig(wrc)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].WriteRelabelConfigs = make([]po_types.RelabelConfig, len(c))
+			for j, rc := range c {
+				obj[i].WriteRelabelConfigs[j] = *rc
+			}
+		}
+		if ba, ok := in["basic_auth"].([]interface{}); ok && len(ba) > 0 {
+			ba, err := expandBasicAuth(ba)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].BasicAuth = ba
+		}
+		obj[i].BearerTokenFile = in["bearer_token_file"].(string)
+		if tls, ok := in["tls_config"].([]interface{}); ok && len(tls) > 0 {
+			tls, err := expandTLSConfig(tls)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].TLSConfig = tls
+		}
+		obj[i].ProxyURL = in["proxy_url"].(string)
+		if qc, ok := in["queue_config"].([]interface{}); ok && len(qc) > 0 && qc[0] != nil {
+			obj[i].QueueConfig = expandQueueConfig(qc[0].(map[string]interface{}))
+		}
+	}
+	return obj, nil
+}
+
+func expandQueueConfig(in map[string]interface{}) *po_types.QueueConfig {
+	return &po_types.QueueConfig{
+		Capacity:          in["capacity"].(int),
+		MinShards:         in["min_shards"].(int),
+		MaxShards:         in["max_shards"].(int),
+		MaxSamplesPerSend: in["max_samples_per_send"].(int),
+		BatchSendDeadline: in["batch_send_deadline"].(string),
+		MaxRetries:        in["max_retries"].(int),
+		MinBackoff:        in["min_backoff"].(string),
+		MaxBackoff:        in["max_backoff"].(string),
+	}
+}
+
+func flattenRemoteWriteSpecs(in []po_types.RemoteWriteSpec) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, v := range in {
+		e := make(map[string]interface{})
+		e["url"] = v.URL
+		e["name"] = v.Name
+		e["remote_timeout"] = v.RemoteTimeout
+		e["headers"] = v.Headers
+		relabelings := make([]*po_types.RelabelConfig, len(v.WriteRelabelConfigs))
+		for j := range v.WriteRelabelConfigs {
+			relabelings[j] = &v.WriteRelabelConfigs[j]
+		}
+		e["write_relabel_configs"] = flattenRelabelConfig(relabelings)
+		if v.BasicAuth != nil {
+			e["basic_auth"] = flattenBasicAuth(v.BasicAuth)
+		}
+		e["bearer_token_file"] = v.BearerTokenFile
+		if v.TLSConfig != nil {
+			e["tls_config"] = flattenTLSConfig(v.TLSConfig)
+		}
+		e["proxy_url"] = v.ProxyURL
+		if v.QueueConfig != nil {
+			e["queue_config"] = []interface{}{map[string]interface{}{
+				"capacity":             v.QueueConfig.Capacity,
+				"min_shards":           v.QueueConfig.MinShards,
+				"max_shards":           v.QueueConfig.MaxShards,
+				"max_samples_per_send": v.QueueConfig.MaxSamplesPerSend,
+				"batch_send_deadline":  v.QueueConfig.BatchSendDeadline,
+				"max_retries":          v.QueueConfig.MaxRetries,
+				"min_backoff":          v.QueueConfig.MinBackoff,
+				"max_backoff":          v.QueueConfig.MaxBackoff,
+			}}
+		}
+		att[i] = e
+	}
+	return att
+}
+
+func expandRemoteReadSpecs(specs []interface{}) ([]po_types.RemoteReadSpec, error) {
+	if len(specs) == 0 {
+		return []po_types.RemoteReadSpec{}, nil
+	}
+	obj := make([]po_types.RemoteReadSpec, len(specs))
+	for i, e := range specs {
+		in := e.(map[string]interface{})
+		obj[i].URL = in["url"].(string)
+		obj[i].Name = in["name"].(string)
+		if rm, ok := in["required_matchers"].(map[string]interface{}); ok && len(rm) > 0 {
+			obj[i].RequiredMatchers = expandStringMap(rm)
+		}
+		obj[i].RemoteTimeout = in["remote_timeout"].(string)
+		obj[i].ReadRecent = in["read_recent"].(bool)
+		if ba, ok := in["basic_auth"].([]interface{}); ok && len(ba) > 0 {
+			ba, err := expandBasicAuth(ba)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].BasicAuth = ba
+		}
+		obj[i].BearerTokenFile = in["bearer_token_file"].(string)
+		if tls, ok := in["tls_config"].([]interface{}); ok && len(tls) > 0 {
+			tls, err := expandTLSConfig(tls)
+			if err != nil {
+				return obj, err
+			}
+			obj[i].TLSConfig = tls
+		}
+		obj[i].ProxyURL = in["proxy_url"].(string)
+	}
+	return obj, nil
+}
+
+func flattenRemoteReadSpecs(in []po_types.RemoteReadSpec) []interface{} {
+	att := make([]interface{}, len(in))
+	for i, v := range in {
+		e := make(map[string]interface{})
+		e["url"] = v.URL
+		e["name"] = v.Name
+		e["required_matchers"] = v.RequiredMatchers
+		e["remote_timeout"] = v.RemoteTimeout
+		e["read_recent"] = v.ReadRecent
+		if v.BasicAuth != nil {
+			e["basic_auth"] = flattenBasicAuth(v.BasicAuth)
+		}
+		e["bearer_token_file"] = v.BearerTokenFile
+		if v.TLSConfig != nil {
+			e["tls_config"] = flattenTLSConfig(v.TLSConfig)
+		}
+		e["proxy_url"] = v.ProxyURL
+		att[i] = e
+	}
+	return att
+}
+
+func expandStorageSpec(l []interface{}) (*po_types.StorageSpec, error) {
+	obj := &po_types.StorageSpec{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
+	obj.DisableMountSubPath = in["disable_mount_sub_path"].(bool)
+	if v, ok := in["empty_dir"].([]interface{}); ok && len(v) > 0 {
+		obj.EmptyDir = &v1.EmptyDirVolumeSource{}
+		if v[0] != nil {
+			ed := v[0].(map[string]interface{})
+			obj.EmptyDir.Medium = v1.StorageMedium(ed["medium"].(string))
+			if sl, ok := ed["size_limit"].(string); ok && sl != "" {
+				q, err := resource.ParseQuantity(sl)
+				if err != nil {
+					return obj, err
+				}
+				obj.EmptyDir.SizeLimit = &q
+			}
+		}
+	}
+	if v, ok := in["volume_claim_template"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		vct := v[0].(map[string]interface{})
+		if m, ok := vct["metadata"].([]interface{}); ok && len(m) > 0 {
+			obj.VolumeClaimTemplate.EmbeddedObjectMetadata = expandEmbeddedObjectMetadata(m)
+		}
+		spec, err := expandPersistentVolumeClaimSpec(vct["spec"].([]interface{}))
+		if err != nil {
+			return obj, err
+		}
+		obj.VolumeClaimTemplate.Spec = *spec
+	}
+	return obj, nil
+}
+
+func flattenStorageSpec(in *po_types.StorageSpec) []interface{} {
+	att := make(map[string]interface{})
+	att["disable_mount_sub_path"] = in.DisableMountSubPath
+	if in.EmptyDir != nil {
+		ed := make(map[string]interface{})
+		ed["medium"] = string(in.EmptyDir.Medium)
+		if in.EmptyDir.SizeLimit != nil {
+			ed["size_limit"] = in.EmptyDir.SizeLimit.String()
+		}
+		att["empty_dir"] = []interface{}{ed}
+	}
+	if len(in.VolumeClaimTemplate.Spec.AccessModes) > 0 {
+		vct := make(map[string]interface{})
+		meta := in.VolumeClaimTemplate.EmbeddedObjectMetadata
+		if meta.Name != "" || len(meta.Labels) > 0 || len(meta.Annotations) > 0 {
+			vct["metadata"] = flattenEmbeddedObjectMetadata(meta)
+		}
+		vct["spec"] = flattenPersistentVolumeClaimSpec(in.VolumeClaimTemplate.Spec)
+		att["volume_claim_template"] = []interface{}{vct}
+	}
+	return []interface{}{att}
+}
+
+func expandEmbeddedObjectMetadata(l []interface{}) po_types.EmbeddedObjectMetadata {
+	obj := po_types.EmbeddedObjectMetadata{}
+	if len(l) == 0 || l[0] == nil {
+		return obj
+	}
+	in := l[0].(map[string]interface{})
+	obj.Name = in["name"].(string)
+	if v, ok := in["labels"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.Labels = expandStringMap(v)
+	}
+	if v, ok := in["annotations"].(map[string]interface{}); ok && len(v) > 0 {
+		obj.Annotations = expandStringMap(v)
+	}
+	return obj
+}
+
+func flattenEmbeddedObjectMetadata(in po_types.EmbeddedObjectMetadata) []interface{} {
+	att := make(map[string]interface{})
+	att["name"] = in.Name
+	att["labels"] = in.Labels
+	att["annotations"] = in.Annotations
+	return []interface{}{att}
+}
+
+// expandOptionalSecretKeyRef returns nil when the selector block is absent so
+// that omitted secrets are not sent as empty references.
+func expandOptionalSecretKeyRef(l interface{}) (*v1.SecretKeySelector, error) {
+	v, ok := l.([]interface{})
+	if !ok || len(v) == 0 || v[0] == nil {
+		return nil, nil
+	}
+	return expandSecretKeyRef(v)
+}
+
+func flattenOptionalSecretKeyRef(in *v1.SecretKeySelector) []interface{} {
+	if in == nil {
+		return nil
+	}
+	return flattenSecretKeyRef(in)
+}
@@ -0,0 +1,60 @@
+package po
+
+import (
+	"testing"
+
+	po_types "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestFlattenPodMonitorSpecOmitsEmptySelectors(t *testing.T) {
+	out, err := flattenPodMonitorSpec(po_types.PodMonitorSpec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	att := out[0].(map[string]interface{})
+	for _, k := range []string{"selector", "namespace_selector"} {
+		if got := att[k].([]interface{}); len(got) != 0 {
+			t.Fatalf("expected no %s block, got %#v", k, got)
+		}
+	}
+
+	out, err = flattenPodMonitorSpec(po_types.PodMonitorSpec{
+		Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"app": "example"}},
+		NamespaceSelector: po_types.NamespaceSelector{Any: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	att = out[0].(map[string]interface{})
+	for _, k := range []string{"selector", "namespace_selector"} {
+		if got := att[k].([]interface{}); len(got) != 1 {
+			t.Fatalf("expected one %s block, got %#v", k, got)
+		}
+	}
+}
+
+func TestExpandRulesKeepsNonCanonicalNumbers(t *testing.T) {
+	cases := map[string]intstr.IntOrString{
+		"1":          intstr.FromInt(1),
+		"-1":         intstr.FromInt(-1),
+		"01":         intstr.FromString("01"),
+		"+1":         intstr.FromString("+1"),
+		"-0":         intstr.FromString("-0"),
+		"4294967296": intstr.FromString("4294967296"),
+		"up == 0":    intstr.FromString("up == 0"),
+	}
+	for in, want := range cases {
+		rules, err := expandRules([]interface{}{map[string]interface{}{"alert": "Example", "expr": in}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rules[0].Expr; got != want {
+			t.Fatalf("expected %q to expand to %#v, got %#v", in, want, got)
+		}
+		if got := flattenRules(rules)[0].(map[string]interface{})["expr"]; got != in {
+			t.Fatalf("expected %q to flatten back unchanged, got %q", in, got)
+		}
+	}
+}
@@ -7,29 +7,34 @@ import (
 	"strings"
 )
 
-func diffStringMap(pathPrefix string, oldV, newV map[string]interface{}) PatchOperations {
+// diffStringMap returns the operations that turn the live map into one
+// holding the keys of newV. Only keys that Terraform managed before (oldV)
+// are removed. The map is replaced as a whole only when the live object has
+// none, as other map items may be managed outside of TF and we don't want
+// to touch these.
+func diffStringMap(pathPrefix string, live map[string]string, oldV, newV map[string]interface{}) PatchOperations {
 	ops := make([]PatchOperation, 0, 0)
 
 	pathPrefix = strings.TrimRight(pathPrefix, "/")
 
-	// If old value was empty, just create the object
-	if len(oldV) == 0 {
-		ops = append(ops, &AddOperation{
-			Path:  pathPrefix,
-			Value: newV,
-		})
+	// If the live object has no map, just create it
+	if len(live) == 0 {
+		if len(newV) > 0 {
+			ops = append(ops, &AddOperation{
+				Path:  pathPrefix,
+				Value: newV,
+			})
+		}
 		return ops
 	}
 
-	// This is suboptimal for adding whole new map from scratch
-	// or deleting the whole map, but it's actually intention.
-	// There may be some other map items managed outside of TF
-	// and we don't want to touch these.
-
 	for k := range oldV {
 		if _, ok := newV[k]; ok {
 			continue
 		}
+		if _, ok := live[k]; !ok {
+			continue
+		}
 		ops = append(ops, &RemoveOperation{
 			Path: pathPrefix + "/" + escapeJsonPointer(k),
 		})
@@ -38,7 +43,7 @@ func diffStringMap(pathPrefix string, oldV, newV map[string]interface{}) PatchOp
 	for k, v := range newV {
 		newValue := v.(string)
 
-		if oldValue, ok := oldV[k].(string); ok {
+		if oldValue, ok := live[k]; ok {
 			if oldValue == newValue {
 				continue
 			}
@@ -67,6 +72,13 @@ func escapeJsonPointer(path string) string {
 	return path
 }
 
+// unescapeJsonPointer reverses escapeJsonPointer
+func unescapeJsonPointer(path string) string {
+	path = strings.Replace(path, "~1", "/", -1)
+	path = strings.Replace(path, "~0", "~", -1)
+	return path
+}
+
 type PatchOperations []PatchOperation
 
 func (po PatchOperations) MarshalJSON() ([]byte, error) {
@@ -153,7 +165,6 @@ func (o *RemoveOperation) String() string {
 	return string(b)
 }
 
-
 func replace(spec interface{}) *ReplaceOperation {
 	return &ReplaceOperation{
 		Path:  "/spec",
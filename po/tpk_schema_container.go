@@ -0,0 +1,26 @@
+package po
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourcesField() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"limits": {
+			Type:             schema.TypeMap,
+			Description:      "Describes the maximum amount of compute resources allowed. More info: http://kubernetes.io/docs/user-guide/compute-resources/",
+			Optional:         true,
+			Elem:             &schema.Schema{Type: schema.TypeString},
+			ValidateFunc:     validateResourceList,
+			DiffSuppressFunc: suppressEquivalentResourceQuantity,
+		},
+		"requests": {
+			Type:             schema.TypeMap,
+			Description:      "Describes the minimum amount of compute resources required. More info: http://kubernetes.io/docs/user-guide/compute-resources/",
+			Optional:         true,
+			Elem:             &schema.Schema{Type: schema.TypeString},
+			ValidateFunc:     validateResourceList,
+			DiffSuppressFunc: suppressEquivalentResourceQuantity,
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package po
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func persistentVolumeClaimSpecFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"access_modes": {
+			Type:        schema.TypeSet,
+			Description: "A set of the desired access modes the volume should have. More info: http://kubernetes.io/docs/user-guide/persistent-volumes#access-modes-1",
+			Required:    true,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validateAttributeValueIsIn([]string{"ReadWriteOnce", "ReadOnlyMany", "ReadWriteMany"}),
+			},
+			Set: schema.HashString,
+		},
+		"resources": {
+			Type:        schema.TypeList,
+			Description: "A list of the minimum resources the volume should have. More info: http://kubernetes.io/docs/user-guide/persistent-volumes#resources",
+			Required:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: resourcesField(),
+			},
+		},
+		"selector": {
+			Type:        schema.TypeList,
+			Description: "A label query over volumes to consider for binding.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: labelSelectorFields(false),
+			},
+		},
+		"volume_name": {
+			Type:        schema.TypeString,
+			Description: "The binding reference to the PersistentVolume backing this claim.",
+			Optional:    true,
+			Computed:    true,
+		},
+		"storage_class_name": {
+			Type:        schema.TypeString,
+			Description: "Name of the storage class requested by the claim",
+			Optional:    true,
+			Computed:    true,
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package po
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func tolerationFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"effect": {
+			Type:         schema.TypeString,
+			Description:  "Effect indicates the taint effect to match. Empty means match all taint effects. When specified, allowed values are NoSchedule, PreferNoSchedule and NoExecute.",
+			Optional:     true,
+			ValidateFunc: validateAttributeValueIsIn([]string{"NoSchedule", "PreferNoSchedule", "NoExecute"}),
+		},
+		"key": {
+			Type:        schema.TypeString,
+			Description: "Key is the taint key that the toleration applies to. Empty means match all taint keys. If the key is empty, operator must be Exists; this combination means to match all values and all keys.",
+			Optional:    true,
+		},
+		"operator": {
+			Type:         schema.TypeString,
+			Description:  "Operator represents a key's relationship to the value. Valid operators are Exists and Equal. Defaults to Equal. Exists is equivalent to wildcard for value, so that a pod can tolerate all taints of a particular category.",
+			Default:      "Equal",
+			Optional:     true,
+			ValidateFunc: validateAttributeValueIsIn([]string{"Exists", "Equal"}),
+		},
+		"toleration_seconds": {
+			// Use TypeString to allow an "empty" value
+			Type:         schema.TypeString,
+			Description:  "TolerationSeconds represents the period of time the toleration (which must be of effect NoExecute, otherwise this field is ignored) tolerates the taint. By default, it is not set, which means tolerate the taint forever (do not evict). Zero and negative values will be treated as 0 (evict immediately) by the system.",
+			Optional:     true,
+			ValidateFunc: validateTypeStringNullableInt,
+		},
+		"value": {
+			Type:        schema.TypeString,
+			Description: "Value is the taint value the toleration matches to. If the operator is Exists, the value should be empty, otherwise just a regular string.",
+			Optional:    true,
+		},
+	}
+}
+
+func podSecurityContextFields() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"fs_group": {
+			Type:         schema.TypeString,
+			Description:  "A special supplemental group that applies to all containers in a pod. Some volume types allow the Kubelet to change the ownership of that volume to be owned by the pod: 1. The owning GID will be the FSGroup 2. The setgid bit is set (new files created in the volume will be owned by FSGroup) 3. The permission bits are OR'd with rw-rw---- If unset, the Kubelet will not modify the ownership and permissions of any volume.",
+			Optional:     true,
+			ValidateFunc: validateTypeStringNullableInt,
+		},
+		"run_as_group": {
+			Type:         schema.TypeString,
+			Description:  "The GID to run the entrypoint of the container process. Uses runtime default if unset. May also be set in SecurityContext. If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence for that container.",
+			Optional:     true,
+			ValidateFunc: validateTypeStringNullableInt,
+		},
+		"run_as_non_root": {
+			Type:        schema.TypeBool,
+			Description: "Indicates that the container must run as a non-root user. If true, the Kubelet will validate the image at runtime to ensure that it does not run as UID 0 (root) and fail to start the container if it does. If unset or false, no such validation will be performed. May also be set in SecurityContext. If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence.",
+			Optional:    true,
+		},
+		"run_as_user": {
+			Type:         schema.TypeString,
+			Description:  "The UID to run the entrypoint of the container process. Defaults to user specified in image metadata if unspecified. May also be set in SecurityContext. If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence for that container.",
+			Optional:     true,
+			ValidateFunc: validateTypeStringNullableInt,
+		},
+		"se_linux_options": {
+			Type:        schema.TypeList,
+			Description: "The SELinux context to be applied to all containers. If unspecified, the container runtime will allocate a random SELinux context for each container. May also be set in SecurityContext. If set in both SecurityContext and PodSecurityContext, the value specified in SecurityContext takes precedence for that container.",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: seLinuxOptionsField(),
+			},
+		},
+		"supplemental_groups": {
+			Type:        schema.TypeSet,
+			Description: "A list of groups applied to the first process run in each container, in addition to the container's primary GID. If unspecified, no groups will be added to any container.",
+			Optional:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeInt,
+			},
+		},
+	}
+}
+
+func seLinuxOptionsField() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"level": {
+			Type:        schema.TypeString,
+			Description: "Level is SELinux level label that applies to the container.",
+			Optional:    true,
+		},
+		"role": {
+			Type:        schema.TypeString,
+			Description: "Role is a SELinux role label that applies to the container.",
+			Optional:    true,
+		},
+		"type": {
+			Type:        schema.TypeString,
+			Description: "Type is a SELinux type label that applies to the container.",
+			Optional:    true,
+		},
+		"user": {
+			Type:        schema.TypeString,
+			Description: "User is a SELinux user label that applies to the container.",
+			Optional:    true,
+		},
+	}
+}
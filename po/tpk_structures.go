@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -26,19 +27,21 @@ func buildId(meta metav1.ObjectMeta) string {
 	return meta.Namespace + "/" + meta.Name
 }
 
-func expandMetadata(in []interface{}) metav1.ObjectMeta {
+func expandMetadata(in []interface{}, mc MetadataConfig) metav1.ObjectMeta {
 	meta := metav1.ObjectMeta{}
 	if len(in) < 1 {
 		return meta
 	}
 	m := in[0].(map[string]interface{})
 
-	if v, ok := m["annotations"].(map[string]interface{}); ok && len(v) > 0 {
-		meta.Annotations = expandStringMap(m["annotations"].(map[string]interface{}))
+	annotations, _ := m["annotations"].(map[string]interface{})
+	if len(annotations) > 0 || len(mc.DefaultAnnotations) > 0 {
+		meta.Annotations = expandStringMap(mergeDefaults(mc.DefaultAnnotations, annotations))
 	}
 
-	if v, ok := m["labels"].(map[string]interface{}); ok && len(v) > 0 {
-		meta.Labels = expandStringMap(m["labels"].(map[string]interface{}))
+	labels, _ := m["labels"].(map[string]interface{})
+	if len(labels) > 0 || len(mc.DefaultLabels) > 0 {
+		meta.Labels = expandStringMap(mergeDefaults(mc.DefaultLabels, labels))
 	}
 
 	if v, ok := m["generate_name"]; ok {
@@ -50,23 +53,43 @@ func expandMetadata(in []interface{}) metav1.ObjectMeta {
 	if v, ok := m["namespace"]; ok {
 		meta.Namespace = v.(string)
 	}
+	if meta.Namespace == "" {
+		meta.Namespace = mc.DefaultNamespace
+	}
 
 	return meta
 }
 
-func patchMetadata(keyPrefix, pathPrefix string, d *schema.ResourceData) PatchOperations {
+// patchMetadata diffs the labels and annotations of the live object against
+// the configured ones merged with the provider defaults. State never holds
+// keys that match a provider default (see flattenMetadata), so the merge
+// keeps those keys from being removed and restores defaults that drifted or
+// were added since the object was last written, on every update. Diffing
+// against the live object rather than state keeps keys hidden from state
+// (internal, ignored or default ones) when the first key is added.
+func patchMetadata(keyPrefix, pathPrefix string, d *schema.ResourceData, live metav1.ObjectMeta, mc MetadataConfig) PatchOperations {
 	ops := make([]PatchOperation, 0, 0)
-	if d.HasChange(keyPrefix + "annotations") {
-		oldV, newV := d.GetChange(keyPrefix + "annotations")
-		diffOps := diffStringMap(pathPrefix+"annotations", oldV.(map[string]interface{}), newV.(map[string]interface{}))
-		ops = append(ops, diffOps...)
+	oldV, newV := d.GetChange(keyPrefix + "annotations")
+	diffOps := diffStringMap(pathPrefix+"annotations", live.Annotations, oldV.(map[string]interface{}), mergeDefaults(mc.DefaultAnnotations, newV.(map[string]interface{})))
+	ops = append(ops, removeIgnoredKeyOps(diffOps, pathPrefix+"annotations", mc.IgnoreAnnotations)...)
+
+	oldV, newV = d.GetChange(keyPrefix + "labels")
+	diffOps = diffStringMap(pathPrefix+"labels", live.Labels, oldV.(map[string]interface{}), mergeDefaults(mc.DefaultLabels, newV.(map[string]interface{})))
+	ops = append(ops, removeIgnoredKeyOps(diffOps, pathPrefix+"labels", mc.IgnoreLabels)...)
+	return ops
+}
+
+// mergeDefaults overlays the configured values on top of the provider
+// defaults; configured values win on conflicts.
+func mergeDefaults(defaults map[string]string, configured map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(defaults)+len(configured))
+	for k, v := range defaults {
+		merged[k] = v
 	}
-	if d.HasChange(keyPrefix + "labels") {
-		oldV, newV := d.GetChange(keyPrefix + "labels")
-		diffOps := diffStringMap(pathPrefix+"labels", oldV.(map[string]interface{}), newV.(map[string]interface{}))
-		ops = append(ops, diffOps...)
+	for k, v := range configured {
+		merged[k] = v
 	}
-	return ops
+	return merged
 }
 
 func expandStringMap(m map[string]interface{}) map[string]string {
@@ -109,19 +132,21 @@ func expandStringSlice(s []interface{}) []string {
 	return result
 }
 
-func flattenMetadata(meta metav1.ObjectMeta, d *schema.ResourceData, metaPrefix ...string) []interface{} {
+func flattenMetadata(meta metav1.ObjectMeta, d *schema.ResourceData, mc MetadataConfig, metaPrefix ...string) []interface{} {
 	m := make(map[string]interface{})
 	prefix := ""
 	if len(metaPrefix) > 0 {
 		prefix = metaPrefix[0]
 	}
 	configAnnotations := d.Get(prefix + "metadata.0.annotations").(map[string]interface{})
-	m["annotations"] = removeInternalKeys(meta.Annotations, configAnnotations)
+	annotations := removeDefaultKeys(meta.Annotations, mc.DefaultAnnotations, configAnnotations)
+	m["annotations"] = removeInternalKeys(annotations, configAnnotations, mc.IgnoreAnnotations)
 	if meta.GenerateName != "" {
 		m["generate_name"] = meta.GenerateName
 	}
 	configLabels := d.Get(prefix + "metadata.0.labels").(map[string]interface{})
-	m["labels"] = removeInternalKeys(meta.Labels, configLabels)
+	labels := removeDefaultKeys(meta.Labels, mc.DefaultLabels, configLabels)
+	m["labels"] = removeInternalKeys(labels, configLabels, mc.IgnoreLabels)
 	m["name"] = meta.Name
 	m["resource_version"] = meta.ResourceVersion
 	m["uid"] = fmt.Sprintf("%v", meta.UID)
@@ -134,15 +159,99 @@ func flattenMetadata(meta metav1.ObjectMeta, d *schema.ResourceData, metaPrefix
 	return []interface{}{m}
 }
 
-func removeInternalKeys(m map[string]string, d map[string]interface{}) map[string]string {
+// flattenDataSourceMetadata flattens the metadata of an object read by a
+// data source as is, since there is no configuration to drift from.
+func flattenDataSourceMetadata(meta metav1.ObjectMeta) []interface{} {
+	m := make(map[string]interface{})
+	m["annotations"] = meta.Annotations
+	if meta.GenerateName != "" {
+		m["generate_name"] = meta.GenerateName
+	}
+	m["labels"] = meta.Labels
+	m["name"] = meta.Name
+	m["resource_version"] = meta.ResourceVersion
+	m["uid"] = fmt.Sprintf("%v", meta.UID)
+	m["generation"] = meta.Generation
+	if meta.Namespace != "" {
+		m["namespace"] = meta.Namespace
+	}
+	return []interface{}{m}
+}
+
+// removeDefaultKeys drops keys that come from the provider defaults so they
+// don't show up as drift on resources that don't configure them. Their
+// values are tracked by appliedDefaults instead.
+func removeDefaultKeys(m map[string]string, defaults map[string]string, d map[string]interface{}) map[string]string {
 	for k := range m {
-		if isInternalKey(k) && !isKeyInMap(k, d) {
+		if _, ok := defaults[k]; ok && !isKeyInMap(k, d) {
 			delete(m, k)
 		}
 	}
 	return m
 }
 
+// appliedDefaults returns the values the object has for the provider
+// defaults the configuration does not override.
+func appliedDefaults(live map[string]string, defaults map[string]string, configured map[string]interface{}) map[string]interface{} {
+	applied := make(map[string]interface{})
+	for k := range defaults {
+		if v, ok := live[k]; ok && !isKeyInMap(k, configured) {
+			applied[k] = v
+		}
+	}
+	return applied
+}
+
+// plannedDefaults returns the provider defaults the configuration does not
+// override.
+func plannedDefaults(defaults map[string]string, configured map[string]interface{}) map[string]interface{} {
+	planned := make(map[string]interface{})
+	for k, v := range defaults {
+		if !isKeyInMap(k, configured) {
+			planned[k] = v
+		}
+	}
+	return planned
+}
+
+func removeInternalKeys(m map[string]string, d map[string]interface{}, ignore []*regexp.Regexp) map[string]string {
+	for k := range m {
+		if (isInternalKey(k) || isIgnoredKey(k, ignore)) && !isKeyInMap(k, d) {
+			delete(m, k)
+		}
+	}
+	return m
+}
+
+func isIgnoredKey(key string, ignore []*regexp.Regexp) bool {
+	for _, re := range ignore {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeIgnoredKeyOps drops remove operations for keys matching the ignore
+// patterns, so keys that stop being managed by Terraform are left in place
+// for whoever else owns them.
+func removeIgnoredKeyOps(ops PatchOperations, pathPrefix string, ignore []*regexp.Regexp) PatchOperations {
+	if len(ignore) == 0 {
+		return ops
+	}
+	res := make([]PatchOperation, 0, len(ops))
+	for _, op := range ops {
+		if rm, ok := op.(*RemoveOperation); ok {
+			key := strings.TrimPrefix(rm.Path, strings.TrimRight(pathPrefix, "/")+"/")
+			if isIgnoredKey(unescapeJsonPointer(key), ignore) {
+				continue
+			}
+		}
+		res = append(res, op)
+	}
+	return res
+}
+
 func isKeyInMap(key string, d map[string]interface{}) bool {
 	if d == nil {
 		return false
@@ -72,3 +72,37 @@ func flattenConfigMapKeyRef(in *v1.ConfigMapKeySelector) []interface{} {
 	}
 	return []interface{}{att}
 }
+
+func flattenContainerResourceRequirements(in v1.ResourceRequirements) []interface{} {
+	att := make(map[string]interface{})
+	if len(in.Limits) > 0 {
+		att["limits"] = flattenResourceList(in.Limits)
+	}
+	if len(in.Requests) > 0 {
+		att["requests"] = flattenResourceList(in.Requests)
+	}
+	return []interface{}{att}
+}
+
+func expandContainerResourceRequirements(l []interface{}) (*v1.ResourceRequirements, error) {
+	obj := &v1.ResourceRequirements{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
+	if v, ok := in["limits"].(map[string]interface{}); ok && len(v) > 0 {
+		rl, err := expandMapToResourceList(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Limits = *rl
+	}
+	if v, ok := in["requests"].(map[string]interface{}); ok && len(v) > 0 {
+		rq, err := expandMapToResourceList(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.Requests = *rq
+	}
+	return obj, nil
+}
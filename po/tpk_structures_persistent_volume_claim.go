@@ -0,0 +1,50 @@
+package po
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Flatteners
+
+func flattenPersistentVolumeClaimSpec(in v1.PersistentVolumeClaimSpec) []interface{} {
+	att := make(map[string]interface{})
+	att["access_modes"] = flattenPersistentVolumeAccessModes(in.AccessModes)
+	att["resources"] = flattenContainerResourceRequirements(in.Resources)
+	if in.Selector != nil {
+		att["selector"] = flattenLabelSelector(in.Selector)
+	}
+	if in.VolumeName != "" {
+		att["volume_name"] = in.VolumeName
+	}
+	if in.StorageClassName != nil {
+		att["storage_class_name"] = *in.StorageClassName
+	}
+	return []interface{}{att}
+}
+
+// Expanders
+
+func expandPersistentVolumeClaimSpec(l []interface{}) (*v1.PersistentVolumeClaimSpec, error) {
+	obj := &v1.PersistentVolumeClaimSpec{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
+	resourceRequirements, err := expandContainerResourceRequirements(in["resources"].([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	obj.AccessModes = expandPersistentVolumeAccessModes(in["access_modes"].(*schema.Set).List())
+	obj.Resources = *resourceRequirements
+	if v, ok := in["selector"].([]interface{}); ok && len(v) > 0 {
+		obj.Selector = expandLabelSelector(v)
+	}
+	if v, ok := in["volume_name"].(string); ok {
+		obj.VolumeName = v
+	}
+	if v, ok := in["storage_class_name"].(string); ok && v != "" {
+		obj.StorageClassName = ptrToString(v)
+	}
+	return obj, nil
+}
@@ -1,9 +1,42 @@
 package po
 
 import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	v1 "k8s.io/api/core/v1"
 )
 
+// Flatteners
+
+func flattenPodSecurityContext(in *v1.PodSecurityContext) []interface{} {
+	att := make(map[string]interface{})
+	if in.FSGroup != nil {
+		att["fs_group"] = strconv.Itoa(int(*in.FSGroup))
+	}
+	if in.RunAsGroup != nil {
+		att["run_as_group"] = strconv.Itoa(int(*in.RunAsGroup))
+	}
+	if in.RunAsNonRoot != nil {
+		att["run_as_non_root"] = *in.RunAsNonRoot
+	}
+	if in.RunAsUser != nil {
+		att["run_as_user"] = strconv.Itoa(int(*in.RunAsUser))
+	}
+	if in.SELinuxOptions != nil {
+		att["se_linux_options"] = flattenSeLinuxOptions(in.SELinuxOptions)
+	}
+	if len(in.SupplementalGroups) > 0 {
+		att["supplemental_groups"] = newInt64Set(schema.HashSchema(&schema.Schema{
+			Type: schema.TypeInt,
+		}), in.SupplementalGroups)
+	}
+	if len(att) > 0 {
+		return []interface{}{att}
+	}
+	return []interface{}{}
+}
+
 func flattenSeLinuxOptions(in *v1.SELinuxOptions) []interface{} {
 	att := make(map[string]interface{})
 	if in.User != "" {
@@ -12,7 +45,7 @@ func flattenSeLinuxOptions(in *v1.SELinuxOptions) []interface{} {
 	if in.Role != "" {
 		att["role"] = in.Role
 	}
-	if in.User != "" {
+	if in.Type != "" {
 		att["type"] = in.Type
 	}
 	if in.Level != "" {
@@ -21,3 +54,121 @@ func flattenSeLinuxOptions(in *v1.SELinuxOptions) []interface{} {
 	return []interface{}{att}
 }
 
+func flattenTolerations(tolerations []v1.Toleration) []interface{} {
+	att := []interface{}{}
+	for _, v := range tolerations {
+		obj := map[string]interface{}{}
+
+		if v.Effect != "" {
+			obj["effect"] = string(v.Effect)
+		}
+		if v.Key != "" {
+			obj["key"] = v.Key
+		}
+		if v.Operator != "" {
+			obj["operator"] = string(v.Operator)
+		}
+		if v.TolerationSeconds != nil {
+			obj["toleration_seconds"] = strconv.FormatInt(*v.TolerationSeconds, 10)
+		}
+		if v.Value != "" {
+			obj["value"] = v.Value
+		}
+		att = append(att, obj)
+	}
+	return att
+}
+
+// Expanders
+
+func expandPodSecurityContext(l []interface{}) (*v1.PodSecurityContext, error) {
+	obj := &v1.PodSecurityContext{}
+	if len(l) == 0 || l[0] == nil {
+		return obj, nil
+	}
+	in := l[0].(map[string]interface{})
+	if v, ok := in["fs_group"].(string); ok && v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.FSGroup = ptrToInt64(int64(i))
+	}
+	if v, ok := in["run_as_group"].(string); ok && v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.RunAsGroup = ptrToInt64(int64(i))
+	}
+	if v, ok := in["run_as_non_root"].(bool); ok && v {
+		obj.RunAsNonRoot = ptrToBool(v)
+	}
+	if v, ok := in["run_as_user"].(string); ok && v != "" {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return obj, err
+		}
+		obj.RunAsUser = ptrToInt64(int64(i))
+	}
+	if v, ok := in["se_linux_options"].([]interface{}); ok && len(v) > 0 {
+		obj.SELinuxOptions = expandSeLinuxOptions(v)
+	}
+	if v, ok := in["supplemental_groups"].(*schema.Set); ok && v.Len() > 0 {
+		obj.SupplementalGroups = schemaSetToInt64Array(v)
+	}
+	return obj, nil
+}
+
+func expandSeLinuxOptions(l []interface{}) *v1.SELinuxOptions {
+	if len(l) == 0 || l[0] == nil {
+		return &v1.SELinuxOptions{}
+	}
+	in := l[0].(map[string]interface{})
+	obj := &v1.SELinuxOptions{}
+	if v, ok := in["level"]; ok {
+		obj.Level = v.(string)
+	}
+	if v, ok := in["role"]; ok {
+		obj.Role = v.(string)
+	}
+	if v, ok := in["type"]; ok {
+		obj.Type = v.(string)
+	}
+	if v, ok := in["user"]; ok {
+		obj.User = v.(string)
+	}
+	return obj
+}
+
+func expandTolerations(tolerations []interface{}) ([]v1.Toleration, error) {
+	if len(tolerations) == 0 {
+		return []v1.Toleration{}, nil
+	}
+	ts := make([]v1.Toleration, len(tolerations))
+	for i, t := range tolerations {
+		m := t.(map[string]interface{})
+		ts[i] = v1.Toleration{}
+
+		if value, ok := m["effect"].(string); ok {
+			ts[i].Effect = v1.TaintEffect(value)
+		}
+		if value, ok := m["key"].(string); ok {
+			ts[i].Key = value
+		}
+		if value, ok := m["operator"].(string); ok {
+			ts[i].Operator = v1.TolerationOperator(value)
+		}
+		if value, ok := m["toleration_seconds"].(string); ok && value != "" {
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ts[i].TolerationSeconds = ptrToInt64(seconds)
+		}
+		if value, ok := m["value"]; ok {
+			ts[i].Value = value.(string)
+		}
+	}
+	return ts, nil
+}
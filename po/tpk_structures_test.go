@@ -0,0 +1,98 @@
+package po
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPatchMetadataKeepsIgnoredAnnotations(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePoServiceMonitor().Schema, map[string]interface{}{
+		"metadata": []interface{}{map[string]interface{}{
+			"name":        "example",
+			"annotations": map[string]interface{}{"team": "observability"},
+		}},
+	})
+	live := metav1.ObjectMeta{
+		Name:        "example",
+		Annotations: map[string]string{"argocd.argoproj.io/tracking-id": "app:monitoring/ServiceMonitor:default/example"},
+	}
+	mc := MetadataConfig{IgnoreAnnotations: []*regexp.Regexp{regexp.MustCompile(`^argocd\.argoproj\.io/`)}}
+
+	ops := patchMetadata("metadata.0.", "/metadata/", d, live, mc)
+	data, err := ops.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected a single operation, got %s", data)
+	}
+	if got[0]["op"] != "add" || got[0]["path"] != "/metadata/annotations/team" || got[0]["value"] != "observability" {
+		t.Fatalf("expected the annotation to be added on its own, got %s", data)
+	}
+}
+
+func TestPatchMetadataCreatesMissingMap(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePoServiceMonitor().Schema, map[string]interface{}{
+		"metadata": []interface{}{map[string]interface{}{
+			"name":   "example",
+			"labels": map[string]interface{}{"team": "observability"},
+		}},
+	})
+
+	ops := patchMetadata("metadata.0.", "/metadata/", d, metav1.ObjectMeta{Name: "example"}, MetadataConfig{})
+	data, err := ops.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"path":"/metadata/labels","value":{"team":"observability"},"op":"add"}]`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestPatchMetadataAddsDefaultsOnSpecUpdate(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourcePoServiceMonitor().Schema, map[string]interface{}{
+		"metadata": []interface{}{map[string]interface{}{
+			"name": "example",
+		}},
+	})
+	live := metav1.ObjectMeta{
+		Name:   "example",
+		Labels: map[string]string{"app": "example"},
+	}
+	mc := MetadataConfig{DefaultLabels: map[string]string{"managed-by": "terraform"}}
+
+	ops := patchMetadata("metadata.0.", "/metadata/", d, live, mc)
+	data, err := ops.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"path":"/metadata/labels/managed-by","value":"terraform","op":"add"}]`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestFlattenDataSourceMetadataKeepsInternalKeys(t *testing.T) {
+	meta := metav1.ObjectMeta{
+		Name:        "example",
+		Namespace:   "default",
+		Labels:      map[string]string{"app.kubernetes.io/name": "example"},
+		Annotations: map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+	}
+	m := flattenDataSourceMetadata(meta)[0].(map[string]interface{})
+	if got := m["labels"].(map[string]string); got["app.kubernetes.io/name"] != "example" {
+		t.Fatalf("expected the internal label to be kept, got %#v", got)
+	}
+	if got := m["annotations"].(map[string]string); got["kubectl.kubernetes.io/last-applied-configuration"] != "{}" {
+		t.Fatalf("expected the internal annotation to be kept, got %#v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -189,6 +190,14 @@ func validateTerminationGracePeriodSeconds(value interface{}, key string) (ws []
 	return
 }
 
+func validateDuration(value interface{}, key string) (ws []string, es []error) {
+	v := value.(string)
+	if _, err := time.ParseDuration(v); err != nil {
+		es = append(es, fmt.Errorf("%s must be a duration such as \"30s\" or \"1m\": %s", key, err))
+	}
+	return
+}
+
 func validateIntGreaterThan(minValue int) func(value interface{}, key string) (ws []string, es []error) {
 	return func(value interface{}, key string) (ws []string, es []error) {
 		v := value.(int)
@@ -0,0 +1,308 @@
+package po
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/mitchellh/go-homedir"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/feniix/terraform-provider-po/po"
+
+// Span attributes describing the object and operation.
+const (
+	attrKind      = attribute.Key("po.kind")
+	attrNamespace = attribute.Key("po.namespace")
+	attrName      = attribute.Key("po.name")
+	attrOperation = attribute.Key("po.operation")
+	attrRetries   = attribute.Key("po.retries")
+)
+
+// Tracing exports spans of the CRUD functions, of the plan checks and of
+// the requests sent to the Kubernetes API. A nil *Tracing records nothing.
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+}
+
+func expandTracing(ctx context.Context, l []interface{}) (*Tracing, error) {
+	if len(l) == 0 || l[0] == nil {
+		return nil, nil
+	}
+	in := l[0].(map[string]interface{})
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String("terraform-provider-po"),
+		)),
+	}
+	if v, ok := in["otlp_endpoint"].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse otlp_endpoint: %s", err)
+		}
+		exOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+		if u.Scheme == "http" {
+			exOpts = append(exOpts, otlptracehttp.WithInsecure())
+		}
+		if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+			exOpts = append(exOpts, otlptracehttp.WithURLPath(p))
+		}
+		if h := expandStringMap(in["otlp_headers"].(map[string]interface{})); len(h) > 0 {
+			exOpts = append(exOpts, otlptracehttp.WithHeaders(h))
+		}
+		exporter, err := otlptracehttp.New(ctx, exOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to configure OTLP exporter: %s", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	if v, ok := in["file_path"].(string); ok && v != "" {
+		path, err := homedir.Expand(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithSyncer(&jsonFileExporter{path: path}))
+	}
+	return &Tracing{provider: sdktrace.NewTracerProvider(opts...)}, nil
+}
+
+// Tracer returns the tracer spans are started with.
+func (t *Tracing) Tracer() trace.Tracer {
+	if t == nil {
+		return trace.NewNoopTracerProvider().Tracer(tracerName)
+	}
+	return t.provider.Tracer(tracerName)
+}
+
+// flushTimeout bounds how long an operation waits for its spans to be
+// exported, so an unreachable collector does not stall Terraform.
+const flushTimeout = 5 * time.Second
+
+// flush exports the spans ended so far. The provider process is stopped
+// without notice once Terraform is done with it, so spans are flushed after
+// every operation rather than on shutdown. The operation's context may
+// already be done, e.g. on timeout, so it is not used for the export.
+func (t *Tracing) flush() {
+	if t == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := t.provider.ForceFlush(ctx); err != nil {
+		log.Printf("[WARN] Failed to export spans: %s", err)
+	}
+}
+
+// traceOperation runs a CRUD function in a span carrying the kind,
+// namespace and name of the object and the operation.
+func traceOperation(kind, operation string, fn func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		t := meta.(KubeClientsets).Tracing()
+		ctx, span := t.Tracer().Start(ctx, kind+" "+operation, trace.WithAttributes(
+			attrKind.String(kind),
+			attrOperation.String(operation),
+		))
+		namespace, name := operationObject(d)
+
+		diags := fn(ctx, d, meta)
+
+		// Create sets the ID, and with it a defaulted namespace.
+		if d.Id() != "" {
+			namespace, name = operationObject(d)
+		}
+		span.SetAttributes(attrNamespace.String(namespace), attrName.String(name))
+		for _, dg := range diags {
+			if dg.Severity == diag.Error {
+				span.SetStatus(codes.Error, dg.Summary)
+				break
+			}
+		}
+		span.End()
+		t.flush()
+		return diags
+	}
+}
+
+// tracePlan runs a CustomizeDiff function in a "plan" span, so the API
+// requests made while planning are not exported as parentless spans.
+func tracePlan(kind string, fn schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		t := meta.(KubeClientsets).Tracing()
+		ctx, span := t.Tracer().Start(ctx, kind+" plan", trace.WithAttributes(
+			attrKind.String(kind),
+			attrOperation.String("plan"),
+		))
+		namespace, name := d.Get("metadata.0.namespace").(string), d.Get("metadata.0.name").(string)
+		if ns, n, err := idParts(d.Id()); err == nil {
+			namespace, name = ns, n
+		}
+		span.SetAttributes(attrNamespace.String(namespace), attrName.String(name))
+
+		err := fn(ctx, d, meta)
+
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		t.flush()
+		return err
+	}
+}
+
+// operationObject returns the namespace and name of the object d refers to,
+// from its ID when it is set and from its configuration otherwise.
+func operationObject(d *schema.ResourceData) (string, string) {
+	if namespace, name, err := idParts(d.Id()); err == nil {
+		return namespace, name
+	}
+	if name, ok := d.GetOk("metadata.0.name"); ok {
+		return d.Get("metadata.0.namespace").(string), name.(string)
+	}
+	namespace, _ := d.Get("namespace").(string)
+	return namespace, ""
+}
+
+// spanTransport starts a client span for every request sent to the API.
+// It wraps the retrying transport, so one span covers all attempts of a
+// request and records how many retries it took.
+type spanTransport struct {
+	rt     http.RoundTripper
+	tracer trace.Tracer
+}
+
+func newSpanTransport(tracer trace.Tracer) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &spanTransport{rt: rt, tracer: tracer}
+	}
+}
+
+func (t *spanTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(req.Method),
+		semconv.HTTPTargetKey.String(req.URL.Path),
+		semconv.NetPeerNameKey.String(req.URL.Host),
+	}
+	if kind, namespace, name, ok := parseObjectPath(req.URL.Path); ok {
+		attrs = append(attrs, attrKind.String(kind), attrNamespace.String(namespace), attrName.String(name))
+	}
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// jsonFileExporter appends spans to a file, one JSON document per line, so
+// traces can be inspected without a collector.
+type jsonFileExporter struct {
+	mu   sync.Mutex
+	path string
+}
+
+type jsonSpan struct {
+	Name         string                 `json:"name"`
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	Kind         string                 `json:"kind"`
+	StartTime    time.Time              `json:"start_time"`
+	EndTime      time.Time              `json:"end_time"`
+	Status       string                 `json:"status"`
+	Description  string                 `json:"status_description,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Events       []jsonSpanEvent        `json:"events,omitempty"`
+}
+
+type jsonSpanEvent struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+func (e *jsonFileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var buf []byte
+	for _, s := range spans {
+		js := jsonSpan{
+			Name:        s.Name(),
+			TraceID:     s.SpanContext().TraceID().String(),
+			SpanID:      s.SpanContext().SpanID().String(),
+			Kind:        s.SpanKind().String(),
+			StartTime:   s.StartTime().UTC(),
+			EndTime:     s.EndTime().UTC(),
+			Status:      s.Status().Code.String(),
+			Description: s.Status().Description,
+			Attributes:  attributeMap(s.Attributes()),
+		}
+		if s.Parent().HasSpanID() {
+			js.ParentSpanID = s.Parent().SpanID().String()
+		}
+		for _, ev := range s.Events() {
+			js.Events = append(js.Events, jsonSpanEvent{
+				Name:       ev.Name,
+				Time:       ev.Time.UTC(),
+				Attributes: attributeMap(ev.Attributes),
+			})
+		}
+		line, err := json.Marshal(js)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	f, err := os.OpenFile(e.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (e *jsonFileExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[string]interface{} {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]interface{}, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
@@ -0,0 +1,176 @@
+package po
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RetryConfig holds the provider retry policy for transient API errors.
+type RetryConfig struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// delay returns the exponential backoff before the given retry attempt,
+// starting at 1.
+func (rc RetryConfig) delay(attempt int) time.Duration {
+	d := rc.Backoff
+	for i := 1; i < attempt && d < rc.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > rc.MaxBackoff {
+		d = rc.MaxBackoff
+	}
+	return d
+}
+
+// sleep waits for the backoff of the given attempt and returns early when
+// ctx is done.
+func (rc RetryConfig) sleep(ctx context.Context, attempt int) error {
+	t := time.NewTimer(rc.delay(attempt))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retryRoundTripper retries requests failing with transient errors. Throttled
+// requests (429) are retried for every method, since the server rejected them
+// before doing anything; server errors and broken connections are only
+// retried for idempotent methods. Responses carrying Retry-After are left to
+// the REST client, which already waits and retries those up to 10 times;
+// retrying them here as well would multiply the two limits.
+type retryRoundTripper struct {
+	rt     http.RoundTripper
+	policy RetryConfig
+}
+
+func newRetryRoundTripper(policy RetryConfig) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &retryRoundTripper{rt: rt, policy: policy}
+	}
+}
+
+func (t *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.rt.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !t.shouldRetry(req, resp, err) {
+			trace.SpanFromContext(req.Context()).SetAttributes(attrRetries.Int(attempt - 1))
+			return resp, err
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, berr := req.GetBody()
+			if berr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+
+		if resp != nil {
+			log.Printf("[DEBUG] %s %s returned %d, retrying (attempt %d of %d)", req.Method, req.URL.Path, resp.StatusCode, attempt+1, t.policy.MaxAttempts)
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			log.Printf("[DEBUG] %s %s failed: %s, retrying (attempt %d of %d)", req.Method, req.URL.Path, err, attempt+1, t.policy.MaxAttempts)
+		}
+		if serr := t.policy.sleep(req.Context(), attempt); serr != nil {
+			return nil, serr
+		}
+	}
+}
+
+func (t *retryRoundTripper) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if _, ok := err.(*auditError); ok {
+		return false
+	}
+	if resp != nil && resp.Header.Get("Retry-After") != "" {
+		return false
+	}
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !isIdempotentMethod(req.Method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// impersonateUIDRoundTripper sets the Impersonate-Uid header on every request.
+type impersonateUIDRoundTripper struct {
+	rt  http.RoundTripper
+	uid string
+}
+
+func (t *impersonateUIDRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Impersonate-Uid", t.uid)
+	return t.rt.RoundTrip(req)
+}
+
+// retryOnConflict runs update and, while it fails with a conflict caused by
+// a concurrent write, runs it again. update must read the object and build
+// its request from that fresh copy on every call. Field manager conflicts
+// and failed resource version preconditions are returned straight away, as
+// retrying would not change their outcome.
+func retryOnConflict(ctx context.Context, meta interface{}, update func() error) error {
+	policy := meta.(KubeClientsets).RetryConfig()
+	guarded := meta.(KubeClientsets).ApplyConfig().CheckResourceVersion
+	for attempt := 1; ; attempt++ {
+		err := update()
+		if err == nil || attempt >= policy.MaxAttempts || guarded || !isRetryableConflict(err) {
+			trace.SpanFromContext(ctx).SetAttributes(attrRetries.Int(attempt - 1))
+			return err
+		}
+		log.Printf("[DEBUG] Update conflicted, re-reading the object (attempt %d of %d): %s", attempt+1, policy.MaxAttempts, err)
+		if err := policy.sleep(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+func isRetryableConflict(err error) bool {
+	if !errors.IsConflict(err) {
+		return false
+	}
+	if status, ok := err.(errors.APIStatus); ok && status.Status().Details != nil {
+		for _, c := range status.Status().Details.Causes {
+			if c.Type == metav1.CauseTypeFieldManagerConflict {
+				return false
+			}
+		}
+	}
+	return true
+}